@@ -0,0 +1,12 @@
+// Command verify_iam verifies AWS IAM policies; see the iampolicy package.
+package main
+
+import (
+	"os"
+
+	"test3/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:]))
+}
@@ -0,0 +1,125 @@
+// Package iampolicy parses, validates and verifies AWS IAM policies.
+//
+// Parse and Validate check that a policy has the structure IAM requires;
+// Verify also runs the rules, such as the check for a single asterisk as
+// Resource, and reports their findings. The input formats are those of the
+// verify_iam command line: a {PolicyName, PolicyDocument} wrapper, a bare
+// policy document, a trust policy, an `aws iam get-role-policy` or
+// `aws iam get-policy-version` response, or a URL-encoded document.
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Result is the outcome of verifying a policy.
+type Result struct {
+	// Passed is false when a finding of error severity, or the severity
+	// given with WithSeverityThreshold, is not suppressed.
+	Passed   bool
+	Kind     PolicyKind
+	Findings []Finding
+}
+
+// ValidationError reports a policy that does not have the structure IAM
+// requires, as opposed to input that holds no policy at all.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Option configures Verify and verifiers. Options are created with the
+// With functions.
+type Option interface {
+	apply(v *Verifier) error
+}
+
+// optionFunc implements Option with a function changing the verifier.
+type optionFunc func(v *Verifier) error
+
+func (f optionFunc) apply(v *Verifier) error { return f(v) }
+
+// WithConfigFile configures verification from a YAML or JSON configuration
+// file, as the -config flag of the command line does. Overrides for paths
+// do not apply. It replaces the rules and accepted versions of the options
+// before it and adds its suppressions; their other settings are kept.
+func WithConfigFile(path string) Option {
+	return optionFunc(func(v *Verifier) error {
+		cfg, err := LoadConfig(path, "")
+		if err != nil {
+			return err
+		}
+		configured, err := cfg.Verifier("")
+		if err != nil {
+			return err
+		}
+		v.rules = configured.rules
+		v.declared = configured.declared
+		if configured.versions != nil {
+			v.versions = configured.versions
+		}
+		v.suppressions = append(v.suppressions, configured.suppressions...)
+		v.applyWildcardPolicy()
+		return nil
+	})
+}
+
+// Parse decodes a single policy and returns it validated and parsed. It
+// returns a *ValidationError when the policy is not valid.
+func Parse(data []byte) (*Policy, error) {
+	input, err := parseInput(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+	return loadPolicy(input)
+}
+
+// Validate checks the structure of a single policy without running the
+// rules. It returns a *ValidationError when the policy is not valid.
+func Validate(data []byte) error {
+	_, err := Parse(data)
+	return err
+}
+
+// Verify validates a single policy and runs the rules against it. It
+// returns a *ValidationError when the policy is not valid.
+func Verify(data []byte, options ...Option) (*Result, error) {
+	v, err := NewVerifier(options...)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verify(data)
+}
+
+// VerifyIAMRolePolicy verifies a decoded {PolicyName, PolicyDocument}
+// wrapper and reports whether it passes.
+func VerifyIAMRolePolicy(data map[string]interface{}) (bool, error) {
+	return verifyIAMRolePolicy(data)
+}
+
+// Verify validates a single policy and runs the verifier's rules against it.
+// It returns a *ValidationError when the policy is not valid.
+func (v *Verifier) Verify(data []byte) (*Result, error) {
+	input, err := parseInput(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+	if data, ok := input.(map[string]interface{}); ok && (isTerraformPlan(data) || isAuthorizationDetails(data)) {
+		return nil, errors.New("input holds more than one policy")
+	}
+	normalized, kind, err := normalizePolicyInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	kind = v.policyKind(kind)
+	findings, err := v.verifyPolicyInput(normalized, kind)
+	if err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+	return &Result{Passed: v.passed(findings), Kind: kind, Findings: findings}, nil
+}
@@ -0,0 +1,116 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type authorizationDetails struct {
+	RoleDetailList []roleDetail   `json:"RoleDetailList"`
+	Policies       []policyDetail `json:"Policies"`
+}
+
+type roleDetail struct {
+	RoleName                 string      `json:"RoleName"`
+	Arn                      string      `json:"Arn"`
+	AssumeRolePolicyDocument interface{} `json:"AssumeRolePolicyDocument"`
+	RolePolicyList           []struct {
+		PolicyName     string      `json:"PolicyName"`
+		PolicyDocument interface{} `json:"PolicyDocument"`
+	} `json:"RolePolicyList"`
+	AttachedManagedPolicies []struct {
+		PolicyName string `json:"PolicyName"`
+		PolicyArn  string `json:"PolicyArn"`
+	} `json:"AttachedManagedPolicies"`
+}
+
+type policyDetail struct {
+	PolicyName        string `json:"PolicyName"`
+	Arn               string `json:"Arn"`
+	DefaultVersionId  string `json:"DefaultVersionId"`
+	PolicyVersionList []struct {
+		Document         interface{} `json:"Document"`
+		VersionId        string      `json:"VersionId"`
+		IsDefaultVersion bool        `json:"IsDefaultVersion"`
+	} `json:"PolicyVersionList"`
+}
+
+// RoleResult groups the results of every policy that applies to one role.
+type RoleResult struct {
+	RoleName string         `json:"role"`
+	Results  []PolicyResult `json:"policies"`
+}
+
+func isAuthorizationDetails(data map[string]interface{}) bool {
+	_, ok := data["RoleDetailList"]
+	return ok
+}
+
+// defaultDocument returns the default version of a managed policy, which is
+// the one attached roles are actually granted.
+func (p policyDetail) defaultDocument() (interface{}, bool) {
+	for _, version := range p.PolicyVersionList {
+		if version.IsDefaultVersion || version.VersionId == p.DefaultVersionId {
+			return version.Document, true
+		}
+	}
+	return nil, false
+}
+
+// verifyAuthorizationDetails verifies the inline policies, attached managed
+// policies and trust policy of every role in an
+// `aws iam get-account-authorization-details` snapshot.
+func (v *Verifier) verifyAuthorizationDetails(fileData []byte) ([]RoleResult, error) {
+	var details authorizationDetails
+	if err := json.Unmarshal(fileData, &details); err != nil {
+		return nil, err
+	}
+	if len(details.RoleDetailList) == 0 {
+		return nil, errors.New("no roles found in authorization details")
+	}
+
+	managedPolicies := map[string]policyDetail{}
+	for _, policy := range details.Policies {
+		managedPolicies[policy.Arn] = policy
+	}
+
+	var results []RoleResult
+	for _, role := range details.RoleDetailList {
+		roleResults := RoleResult{RoleName: role.RoleName}
+		roleResults.Results = append(roleResults.Results, v.verifyDocument("trust policy", role.AssumeRolePolicyDocument, TrustPolicy))
+		for _, inline := range role.RolePolicyList {
+			roleResults.Results = append(roleResults.Results, v.verifyDocument("inline policy "+inline.PolicyName, inline.PolicyDocument, InlinePolicy))
+		}
+		for _, attached := range role.AttachedManagedPolicies {
+			source := "managed policy " + attached.PolicyArn
+			policy, ok := managedPolicies[attached.PolicyArn]
+			if !ok {
+				roleResults.Results = append(roleResults.Results, PolicyResult{Source: source, Err: errors.New("policy not found in snapshot")})
+				continue
+			}
+			document, ok := policy.defaultDocument()
+			if !ok {
+				roleResults.Results = append(roleResults.Results, PolicyResult{Source: source, Err: errors.New("default policy version not found in snapshot")})
+				continue
+			}
+			policyVerifier := v
+			if isAWSManagedPolicy(attached.PolicyArn) {
+				// The size of AWS managed policies is up to AWS, not
+				// to the account owner.
+				policyVerifier = v.withoutRule(policySizeRule{}.ID())
+			}
+			roleResults.Results = append(roleResults.Results, policyVerifier.verifyDocument(source, document, ManagedPolicy))
+		}
+		results = append(results, roleResults)
+	}
+
+	return results, nil
+}
+
+// isAWSManagedPolicy reports whether a policy ARN names a policy AWS
+// manages, such as arn:aws:iam::aws:policy/ReadOnlyAccess.
+func isAWSManagedPolicy(arn string) bool {
+	parts := strings.SplitN(arn, ":", 6)
+	return len(parts) == 6 && parts[2] == "iam" && parts[4] == "aws"
+}
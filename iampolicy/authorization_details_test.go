@@ -0,0 +1,166 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const authorizationDetailsJSON = `{
+  "UserDetailList": [],
+  "GroupDetailList": [],
+  "RoleDetailList": [
+    {
+      "RoleName": "app",
+      "Arn": "arn:aws:iam::123456789012:role/app",
+      "AssumeRolePolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": {"Service": "ec2.amazonaws.com"}}]},
+      "RolePolicyList": [
+        {
+          "PolicyName": "read",
+          "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}
+        }
+      ],
+      "AttachedManagedPolicies": [
+        {"PolicyName": "deploy", "PolicyArn": "arn:aws:iam::123456789012:policy/deploy"},
+        {"PolicyName": "ReadOnlyAccess", "PolicyArn": "arn:aws:iam::aws:policy/ReadOnlyAccess"}
+      ]
+    },
+    {
+      "RoleName": "public",
+      "Arn": "arn:aws:iam::123456789012:role/public",
+      "AssumeRolePolicyDocument": "%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22sts%3AAssumeRole%22%2C%22Principal%22%3A%7B%22AWS%22%3A%22%2A%22%7D%7D%5D%7D",
+      "RolePolicyList": [],
+      "AttachedManagedPolicies": []
+    }
+  ],
+  "Policies": [
+    {
+      "PolicyName": "deploy",
+      "Arn": "arn:aws:iam::123456789012:policy/deploy",
+      "DefaultVersionId": "v2",
+      "PolicyVersionList": [
+        {"Document": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}]}, "VersionId": "v2", "IsDefaultVersion": true},
+        {"Document": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket"}]}, "VersionId": "v1", "IsDefaultVersion": false}
+      ]
+    }
+  ]
+}`
+
+func TestVerifyAuthorizationDetails(t *testing.T) {
+	results, err := newVerifier().verifyAuthorizationDetails([]byte(authorizationDetailsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		roleName string
+		results  []PolicyResult
+	}{
+		{
+			roleName: "app",
+			results: []PolicyResult{
+				{Source: "trust policy", Result: true},
+				{Source: "inline policy read", Result: true},
+				{Source: "managed policy arn:aws:iam::123456789012:policy/deploy", Result: false},
+				{Source: "managed policy arn:aws:iam::aws:policy/ReadOnlyAccess", Result: false},
+			},
+		},
+		{
+			roleName: "public",
+			results: []PolicyResult{
+				{Source: "trust policy", Result: false},
+			},
+		},
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d roles, but got %d", len(expected), len(results))
+	}
+	for i, role := range expected {
+		if results[i].RoleName != role.roleName {
+			t.Errorf("Expected role '%s', but got '%s'", role.roleName, results[i].RoleName)
+		}
+		if len(results[i].Results) != len(role.results) {
+			t.Fatalf("%s: expected %d results, but got %d", role.roleName, len(role.results), len(results[i].Results))
+		}
+		for j, res := range results[i].Results {
+			if res.Source != role.results[j].Source || res.Result != role.results[j].Result {
+				t.Errorf("%s: expected %s=%t, but got %s=%t (%v)", role.roleName, role.results[j].Source, role.results[j].Result, res.Source, res.Result, res.Err)
+			}
+		}
+	}
+
+	missing := results[0].Results[3].Err
+	if missing == nil || missing.Error() != "policy not found in snapshot" {
+		t.Errorf("Expected error 'policy not found in snapshot', but got %v", missing)
+	}
+}
+
+func TestVerifyAuthorizationDetailsAWSManagedPolicySize(t *testing.T) {
+	var statements []interface{}
+	for i := 0; i < 100; i++ {
+		statements = append(statements, map[string]interface{}{
+			"Sid":      fmt.Sprintf("Read%d", i),
+			"Effect":   "Allow",
+			"Action":   "s3:GetObject",
+			"Resource": fmt.Sprintf("arn:aws:s3:::bucket-%d/*", i),
+		})
+	}
+	document := map[string]interface{}{"Version": "2012-10-17", "Statement": statements}
+
+	testCases := []struct {
+		name     string
+		arn      string
+		expected bool
+	}{
+		{"AWSManaged", "arn:aws:iam::aws:policy/ReadOnlyAccess", true},
+		{"AWSManagedOtherPartition", "arn:aws-cn:iam::aws:policy/ReadOnlyAccess", true},
+		{"CustomerManaged", "arn:aws:iam::123456789012:policy/read", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			details := map[string]interface{}{
+				"RoleDetailList": []interface{}{map[string]interface{}{
+					"RoleName":                 "app",
+					"AssumeRolePolicyDocument": map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"Service": "ec2.amazonaws.com"}}}},
+					"AttachedManagedPolicies":  []interface{}{map[string]interface{}{"PolicyName": "read", "PolicyArn": tc.arn}},
+				}},
+				"Policies": []interface{}{map[string]interface{}{
+					"PolicyName":        "read",
+					"Arn":               tc.arn,
+					"DefaultVersionId":  "v1",
+					"PolicyVersionList": []interface{}{map[string]interface{}{"Document": document, "VersionId": "v1", "IsDefaultVersion": true}},
+				}},
+			}
+			fileData, err := json.Marshal(details)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := newVerifier().verifyAuthorizationDetails(fileData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			managed := results[0].Results[1]
+			if managed.Err != nil || managed.Result != tc.expected {
+				t.Errorf("Expected %s=%t, but got %t with %v (%v)", managed.Source, tc.expected, managed.Result, managed.Findings, managed.Err)
+			}
+		})
+	}
+}
+
+func TestReadJSONsFromFileAuthorizationDetails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "details.json")
+	if err := os.WriteFile(path, []byte(authorizationDetailsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := filePassed(newVerifier(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Errorf("Expected result 'false' for snapshot with wildcard policies, but got true")
+	}
+}
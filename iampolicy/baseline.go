@@ -0,0 +1,168 @@
+package iampolicy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Baseline records known findings so that only new ones are reported. The
+// zero value is an empty baseline, ready to record findings.
+type Baseline struct {
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry identifies a finding by file, rule, Sid and a hash of the
+// normalized statement content, so it survives reformatting and reordering
+// of the policy but not changes to the offending statement.
+type BaselineEntry struct {
+	File      string `json:"file"`
+	Rule      string `json:"rule"`
+	Sid       string `json:"sid,omitempty"`
+	Statement string `json:"statement"`
+}
+
+// LoadBaseline reads a baseline file written by Write.
+func LoadBaseline(path string) (*Baseline, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Baseline{}
+	if err := json.Unmarshal(fileData, b); err != nil {
+		return nil, fmt.Errorf("invalid baseline file '%s': %s", path, err)
+	}
+	return b, nil
+}
+
+// Write writes the baseline to a file, sorted so that it diffs well.
+func (b *Baseline) Write(path string) error {
+	sort.Slice(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		if x.Sid != y.Sid {
+			return x.Sid < y.Sid
+		}
+		return x.Statement < y.Statement
+	})
+	fileData, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(fileData, '\n'), 0644)
+}
+
+func (b *Baseline) contains(entry BaselineEntry) bool {
+	for _, e := range b.Findings {
+		if e == entry {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Baseline) add(entry BaselineEntry) {
+	if !b.contains(entry) {
+		b.Findings = append(b.Findings, entry)
+	}
+}
+
+// WithBaseline hides the findings recorded in the baseline. The number of
+// findings hidden is reported by VerifyFile and VerifyInput.
+func WithBaseline(b *Baseline) Option {
+	return optionFunc(func(v *Verifier) error {
+		v.baseline = b
+		return nil
+	})
+}
+
+// WithBaselineRecord records the unsuppressed findings of the policies
+// verified in b, to be written as a new baseline.
+func WithBaselineRecord(b *Baseline) Option {
+	return optionFunc(func(v *Verifier) error {
+		v.record = b
+		return nil
+	})
+}
+
+// fingerprint hashes the normalized content of the statement a finding is
+// about, or of the whole document for document-wide findings.
+func fingerprint(policy *Policy, finding Finding) string {
+	var content interface{} = policy.Document
+	if finding.Statement >= 0 && finding.Statement < len(policy.Statements) {
+		content = normalizeStatement(policy.Statements[finding.Statement].Raw)
+	}
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeStatement returns the statement without its Sid and with every
+// string-or-list field as a sorted list without duplicates.
+func normalizeStatement(statement map[string]interface{}) map[string]interface{} {
+	normalized := map[string]interface{}{}
+	for key, value := range statement {
+		switch key {
+		case "Sid":
+		case "Action", "NotAction", "Resource", "NotResource":
+			normalized[key] = sortedUnique(stringList(value))
+		case "Principal":
+			if principal, ok := value.(map[string]interface{}); ok {
+				normalizedPrincipal := map[string]interface{}{}
+				for kind, values := range principal {
+					normalizedPrincipal[kind] = sortedUnique(stringList(values))
+				}
+				value = normalizedPrincipal
+			}
+			normalized[key] = value
+		default:
+			normalized[key] = value
+		}
+	}
+	return normalized
+}
+
+func sortedUnique(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// applyBaseline hides the findings already recorded in the baseline and
+// records the remaining ones when a baseline is being written.
+func (v *Verifier) applyBaseline(policy *Policy, findings []Finding) []Finding {
+	if v.baseline == nil && v.record == nil {
+		return findings
+	}
+
+	file := filepath.ToSlash(filepath.Clean(v.file))
+	var remaining []Finding
+	for _, finding := range findings {
+		entry := BaselineEntry{File: file, Rule: finding.RuleID, Sid: finding.Sid, Statement: fingerprint(policy, finding)}
+		if finding.Suppression == nil && v.record != nil {
+			v.record.add(entry)
+		}
+		if v.baseline != nil && v.baseline.contains(entry) {
+			v.baselined++
+			continue
+		}
+		remaining = append(remaining, finding)
+	}
+	return remaining
+}
@@ -0,0 +1,116 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "policy.json")
+	baselineFile := filepath.Join(dir, "baseline.json")
+	writePolicy := func(content string) {
+		if err := os.WriteFile(policyFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writePolicy(describePolicyJSON)
+	v := newVerifier()
+	v.record = &Baseline{}
+	if _, err := filePassed(v, policyFile); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.record.Findings) != 2 {
+		t.Fatalf("Expected 2 recorded findings, but got %v", v.record.Findings)
+	}
+	if err := v.record.Write(baselineFile); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBaseline(baselineFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		policy         string
+		expectedResult bool
+	}{
+		{
+			name:           "Unchanged",
+			policy:         describePolicyJSON,
+			expectedResult: true,
+		},
+		{
+			name: "ReorderedAndReformatted",
+			policy: `{"Statement": [
+				{"Resource": ["*"], "Action": ["ec2:TerminateInstances"], "Effect": "Allow", "Sid": "Terminate"},
+				{"Sid": "Describe", "Effect": "Allow", "Action": "ec2:DescribeInstances", "Resource": "*"}
+			], "Version": "2012-10-17"}`,
+			expectedResult: true,
+		},
+		{
+			name: "ChangedStatement",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Sid": "Describe", "Effect": "Allow", "Action": "ec2:DescribeInstances", "Resource": "*"},
+				{"Sid": "Terminate", "Effect": "Allow", "Action": ["ec2:TerminateInstances", "ec2:StopInstances"], "Resource": "*"}
+			]}`,
+			expectedResult: false,
+		},
+		{
+			name: "NewStatement",
+			policy: `{"Version": "2012-10-17", "Statement": [
+				{"Sid": "Describe", "Effect": "Allow", "Action": "ec2:DescribeInstances", "Resource": "*"},
+				{"Sid": "Terminate", "Effect": "Allow", "Action": "ec2:TerminateInstances", "Resource": "*"},
+				{"Sid": "Delete", "Effect": "Allow", "Action": "s3:DeleteObject", "Resource": "*"}
+			]}`,
+			expectedResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writePolicy(tc.policy)
+			v := newVerifier()
+			v.baseline = b
+			res, err := filePassed(v, policyFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != tc.expectedResult {
+				t.Errorf("Expected result '%t', but got %t", tc.expectedResult, res)
+			}
+		})
+	}
+}
+
+func TestBaselineOtherFile(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte(describePolicyJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorded := &Baseline{}
+	v := newVerifier()
+	v.record = recorded
+	if _, err := filePassed(v, first); err != nil {
+		t.Fatal(err)
+	}
+
+	v = newVerifier()
+	v.baseline = recorded
+	res, err := filePassed(v, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Errorf("Expected findings of another file not to be hidden")
+	}
+}
@@ -0,0 +1,111 @@
+package iampolicy
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+//go:embed catalog/actions.txt
+var embeddedCatalog []byte
+
+// Catalog lists the actions of the services it knows about, so that
+// wildcards can be expanded into the concrete actions they grant. Only
+// services marked complete are listed with every action AWS defines.
+type Catalog struct {
+	actions  []string
+	known    map[string]bool
+	complete map[string]bool
+	// resourceless holds the actions without resource-level permissions,
+	// which are only granted with "Resource": "*".
+	resourceless map[string]bool
+}
+
+// LoadCatalog reads a catalog file with one service:action name per line, or
+// returns the embedded catalog when path is empty. A service:* line marks
+// the catalog's list of actions of the service complete, and an action
+// followed by * has no resource-level permissions.
+func LoadCatalog(path string) (*Catalog, error) {
+	if path == "" {
+		return parseCatalog(embeddedCatalog)
+	}
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := parseCatalog(fileData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return catalog, nil
+}
+
+func parseCatalog(fileData []byte) (*Catalog, error) {
+	catalog := &Catalog{known: map[string]bool{}, complete: map[string]bool{}, resourceless: map[string]bool{}}
+	scanner := bufio.NewScanner(bytes.NewReader(fileData))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		action := fields[0]
+		service, name, ok := strings.Cut(action, ":")
+		if len(fields) == 1 && ok && name == "*" && service != "" && !strings.ContainsAny(service, "*?") {
+			catalog.complete[strings.ToLower(service)] = true
+			continue
+		}
+		if !ok || service == "" || name == "" || strings.ContainsAny(action, "*?") {
+			return nil, fmt.Errorf("line %d: '%s' is not a service:action name", line, action)
+		}
+		if len(fields) > 2 || (len(fields) == 2 && fields[1] != "*") {
+			return nil, fmt.Errorf("line %d: '%s' is not a service:action name, optionally followed by *", line, strings.Join(fields, " "))
+		}
+		if len(fields) == 2 {
+			catalog.resourceless[strings.ToLower(action)] = true
+		}
+		if catalog.known[strings.ToLower(action)] {
+			continue
+		}
+		catalog.known[strings.ToLower(action)] = true
+		catalog.actions = append(catalog.actions, action)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(catalog.actions)
+	return catalog, nil
+}
+
+// contains reports whether action is a concrete action of the catalog.
+func (c *Catalog) contains(action string) bool {
+	return c.known[strings.ToLower(action)]
+}
+
+// hasResources reports whether the action can be granted on specific
+// resources, which is assumed for actions the catalog does not mark.
+func (c *Catalog) hasResources(action string) bool {
+	return !c.resourceless[strings.ToLower(action)]
+}
+
+// covers reports whether the catalog lists every action of the service a
+// pattern is restricted to, i.e. whether the service is marked complete.
+// Patterns whose service part is a wildcard are never covered.
+func (c *Catalog) covers(pattern string) bool {
+	service, _, ok := strings.Cut(pattern, ":")
+	return ok && !strings.ContainsAny(service, "*?") && c.complete[strings.ToLower(service)]
+}
+
+// expand returns the catalog actions matching a pattern.
+func (c *Catalog) expand(pattern string) []string {
+	var actions []string
+	for _, action := range c.actions {
+		if actionMatch(pattern, action) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,241 @@
+package iampolicy
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// cloudTrailRecord holds the fields of a CloudTrail event needed to tell
+// which role called which action on which resources.
+type cloudTrailRecord struct {
+	EventTime          string `json:"eventTime"`
+	EventSource        string `json:"eventSource"`
+	EventName          string `json:"eventName"`
+	AWSRegion          string `json:"awsRegion"`
+	RecipientAccountID string `json:"recipientAccountId"`
+	ErrorCode          string `json:"errorCode"`
+	UserIdentity       struct {
+		ARN            string `json:"arn"`
+		SessionContext struct {
+			SessionIssuer struct {
+				ARN      string `json:"arn"`
+				UserName string `json:"userName"`
+			} `json:"sessionIssuer"`
+		} `json:"sessionContext"`
+	} `json:"userIdentity"`
+	Resources []struct {
+		ARN string `json:"ARN"`
+	} `json:"resources"`
+}
+
+// eventSourcePrefixes maps event sources whose name differs from the IAM
+// service prefix of their actions.
+var eventSourcePrefixes = map[string]string{
+	"monitoring": "cloudwatch",
+	"email":      "ses",
+}
+
+// eventActions maps events authorized by an action of another name.
+var eventActions = map[string]string{
+	"s3:ListObjects":   "s3:ListBucket",
+	"s3:ListObjectsV2": "s3:ListBucket",
+	"s3:HeadObject":    "s3:GetObject",
+	"s3:HeadBucket":    "s3:ListBucket",
+	"s3:ListBuckets":   "s3:ListAllMyBuckets",
+	"lambda:Invoke":    "lambda:InvokeFunction",
+}
+
+// apiVersionSuffix matches the API version some services, e.g. Lambda,
+// append to event names, as in CreateFunction20150331v2.
+var apiVersionSuffix = regexp.MustCompile(`\d{8}(v\d+)?$`)
+
+// regionlessServices have no region in their ARNs, and accountlessServices
+// no account either, e.g. arn:aws:s3:::bucket.
+var (
+	regionlessServices  = map[string]bool{"iam": true, "sts": true, "s3": true, "cloudfront": true, "organizations": true, "route53": true}
+	accountlessServices = map[string]bool{"s3": true, "route53": true}
+)
+
+// readCloudTrail reads the records of CloudTrail log files, plain or
+// gzip-compressed. Directories are searched recursively for .json and
+// .json.gz files.
+func readCloudTrail(paths []string) ([]cloudTrailRecord, error) {
+	var records []cloudTrailRecord
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			if file != path && !strings.HasSuffix(file, ".json") && !strings.HasSuffix(file, ".json.gz") {
+				return nil
+			}
+			fileRecords, err := readCloudTrailFile(file)
+			if err != nil {
+				return fmt.Errorf("%s: %s", file, err)
+			}
+			records = append(records, fileRecords...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func readCloudTrailFile(path string) ([]cloudTrailRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var log struct {
+		Records []cloudTrailRecord `json:"Records"`
+	}
+	if err := json.NewDecoder(reader).Decode(&log); err != nil {
+		return nil, err
+	}
+	return log.Records, nil
+}
+
+// madeBy reports whether the event was made by a role, given by name or ARN.
+func (r cloudTrailRecord) madeBy(role string) bool {
+	issuer := r.UserIdentity.SessionContext.SessionIssuer
+	return issuer.UserName == role || issuer.ARN == role || r.UserIdentity.ARN == role
+}
+
+// denied reports whether the call failed for lack of permission, in which
+// case it exercised no permission.
+func (r cloudTrailRecord) denied() bool {
+	return strings.Contains(r.ErrorCode, "AccessDenied") || strings.Contains(r.ErrorCode, "Unauthorized")
+}
+
+func (r cloudTrailRecord) service() string {
+	service := strings.TrimSuffix(r.EventSource, ".amazonaws.com")
+	if prefix, ok := eventSourcePrefixes[service]; ok {
+		return prefix
+	}
+	return service
+}
+
+// action returns the IAM action authorizing the event.
+func (r cloudTrailRecord) action() string {
+	action := r.service() + ":" + apiVersionSuffix.ReplaceAllString(r.EventName, "")
+	if mapped, ok := eventActions[action]; ok {
+		return mapped
+	}
+	return action
+}
+
+// resources returns the ARNs of the resources of the event, or a pattern
+// for every resource of the service in the event's account and region when
+// the event names none. The pattern leaves out the region or the account
+// for services whose ARNs have none.
+func (r cloudTrailRecord) resources() []string {
+	if arns := r.resourceARNs(); len(arns) > 0 {
+		return arns
+	}
+
+	partition := "aws"
+	if strings.HasPrefix(r.AWSRegion, "cn-") {
+		partition = "aws-cn"
+	} else if strings.HasPrefix(r.AWSRegion, "us-gov-") {
+		partition = "aws-us-gov"
+	}
+	region, account := r.AWSRegion, r.RecipientAccountID
+	if regionlessServices[r.service()] {
+		region = ""
+	}
+	if accountlessServices[r.service()] {
+		account = ""
+	}
+	return []string{fmt.Sprintf("arn:%s:%s:%s:%s:*", partition, r.service(), region, account)}
+}
+
+// resourceARNs returns the ARNs of the resources the event names, if any;
+// many services name none.
+func (r cloudTrailRecord) resourceARNs() []string {
+	var arns []string
+	for _, resource := range r.Resources {
+		if resource.ARN != "" {
+			arns = append(arns, resource.ARN)
+		}
+	}
+	return arns
+}
+
+// accessLog maps each action a role used to the resources it used it on.
+type accessLog map[string]map[string]bool
+
+// collectAccess gathers the actions and resources used by a role, leaving
+// out denied calls.
+func collectAccess(records []cloudTrailRecord, role string) accessLog {
+	access := accessLog{}
+	for _, record := range records {
+		if !record.madeBy(role) || record.denied() || record.EventName == "" {
+			continue
+		}
+		action := record.action()
+		if access[action] == nil {
+			access[action] = map[string]bool{}
+		}
+		for _, resource := range record.resources() {
+			access[action][resource] = true
+		}
+	}
+	return access
+}
+
+// allowAnyResource replaces the resources of the actions the catalog lists
+// without resource-level permissions by "*", the only Resource granting
+// them, and returns those actions.
+func (a accessLog) allowAnyResource(catalog *Catalog) []string {
+	var resourceless []string
+	for _, action := range a.actions() {
+		if !catalog.hasResources(action) {
+			a[action] = map[string]bool{"*": true}
+			resourceless = append(resourceless, action)
+		}
+	}
+	return resourceless
+}
+
+func (a accessLog) actions() []string {
+	actions := make([]string, 0, len(a))
+	for action := range a {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+func (a accessLog) resources(action string) []string {
+	resources := make([]string, 0, len(a[action]))
+	for resource := range a[action] {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
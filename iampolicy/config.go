@@ -0,0 +1,297 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultConfigFiles are looked up in the working directory when no
+// configuration file is given.
+var defaultConfigFiles = []string{".verify_iam.yaml", ".verify_iam.yml", ".verify_iam.json"}
+
+// Config is the content of a YAML or JSON configuration file. Overrides
+// apply, in order, to input files matching one of their paths.
+type Config struct {
+	// RulesDir holds declarative rules, relative to the configuration file.
+	RulesDir        string                `yaml:"rulesDir"`
+	AllowedVersions []string              `yaml:"allowedVersions"`
+	Rules           map[string]RuleConfig `yaml:"rules"`
+	Overrides       []ConfigOverride      `yaml:"overrides"`
+	Suppressions    []Suppression         `yaml:"suppressions"`
+
+	// declared holds the declarative rules of RulesDir and of the rules
+	// directories given on the command line. Unlike the rules written in
+	// Go they are only known to the verifiers of this configuration.
+	declared []Rule
+}
+
+// RuleConfig enables, disables or sets the severity and options of a rule.
+type RuleConfig struct {
+	Enabled  *bool                  `yaml:"enabled"`
+	Severity string                 `yaml:"severity"`
+	Options  map[string]interface{} `yaml:"options"`
+}
+
+// ConfigOverride changes the configuration for the input files matching
+// one of its paths.
+type ConfigOverride struct {
+	Paths           []string              `yaml:"paths"`
+	AllowedVersions []string              `yaml:"allowedVersions"`
+	Rules           map[string]RuleConfig `yaml:"rules"`
+}
+
+// configurableRule is implemented by rules that accept options from the
+// configuration file. Configure returns a copy of the rule using them.
+type configurableRule interface {
+	Rule
+	Configure(options map[string]interface{}) (Rule, error)
+}
+
+// severityRule reports the findings of a rule with another severity.
+type severityRule struct {
+	Rule
+	severity Severity
+}
+
+func (r severityRule) Severity() Severity { return r.severity }
+
+func (r severityRule) Check(policy *Policy) []Finding {
+	findings := r.Rule.Check(policy)
+	for i := range findings {
+		findings[i].Severity = r.severity
+	}
+	return findings
+}
+
+// LoadConfig reads a configuration file. Without a path it falls back to
+// one of defaultConfigFiles, or to an empty configuration if none exists.
+// The declarative rules of rulesDir, if any, are added to those of the
+// file.
+func LoadConfig(path, rulesDir string) (*Config, error) {
+	if path == "" {
+		for _, name := range defaultConfigFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+	}
+
+	cfg := &Config{}
+	var rulesDirs []string
+	if path != "" {
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeStrict(fileData, cfg); err != nil {
+			return nil, fmt.Errorf("invalid configuration file '%s': %s", path, err)
+		}
+		if cfg.RulesDir != "" {
+			dir := cfg.RulesDir
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(filepath.Dir(path), dir)
+			}
+			rulesDirs = append(rulesDirs, dir)
+		}
+	}
+	if rulesDir != "" {
+		rulesDirs = append(rulesDirs, rulesDir)
+	}
+	for _, dir := range rulesDirs {
+		if err := cfg.declareRules(dir); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range cfg.Suppressions {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("invalid configuration file '%s': %s", path, err)
+		}
+	}
+	if _, err := cfg.Verifier(""); err != nil {
+		return nil, fmt.Errorf("invalid configuration file '%s': %s", path, err)
+	}
+	for _, override := range cfg.Overrides {
+		if len(override.Paths) == 0 {
+			return nil, fmt.Errorf("invalid configuration file '%s': override without paths", path)
+		}
+		if err := cfg.checkOverrideRules(override); err != nil {
+			return nil, fmt.Errorf("invalid configuration file '%s': %s", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// declareRules loads the declarative rules of a directory into the
+// configuration.
+func (c *Config) declareRules(dir string) error {
+	rules, err := loadRulesDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if _, ok := lookupRule(c.declared, rule.ID()); ok {
+			return fmt.Errorf("rule %s from '%s' is already registered", rule.ID(), dir)
+		}
+		c.declared = append(c.declared, rule)
+	}
+	return nil
+}
+
+// checkOverrideRules checks the rule settings of an override as they apply
+// on top of the top-level ones, whichever files its paths match.
+func (c *Config) checkOverrideRules(override ConfigOverride) error {
+	for id, ruleConfig := range override.Rules {
+		rule, ok := lookupRule(c.declared, id)
+		if !ok {
+			return errors.New("unknown rule " + id)
+		}
+		if _, err := c.Rules[id].merge(ruleConfig).apply(rule); err != nil {
+			return fmt.Errorf("rule %s: %s", id, err)
+		}
+	}
+	return nil
+}
+
+// Verifier returns a verifier configured for the given input file.
+func (c *Config) Verifier(path string) (*Verifier, error) {
+	versions := append([]string{}, c.AllowedVersions...)
+	rules := map[string]RuleConfig{}
+	for id, rule := range c.Rules {
+		rules[id] = rule
+	}
+	for _, override := range c.Overrides {
+		if !override.matches(path) {
+			continue
+		}
+		versions = append(versions, override.AllowedVersions...)
+		for id, rule := range override.Rules {
+			rules[id] = rules[id].merge(rule)
+		}
+	}
+
+	v := &Verifier{declared: c.declared}
+	if len(versions) > 0 {
+		v.versions = append([]string{"2012-10-17", "2008-10-17"}, versions...)
+	}
+
+	for _, s := range c.Suppressions {
+		if len(s.Paths) == 0 || (ConfigOverride{Paths: s.Paths}).matches(path) {
+			v.suppressions = append(v.suppressions, s)
+		}
+	}
+
+	for id := range rules {
+		if _, ok := lookupRule(c.declared, id); !ok {
+			return nil, errors.New("unknown rule " + id)
+		}
+	}
+	for _, rule := range registeredRules(c.declared...) {
+		ruleConfig := rules[rule.ID()]
+		if optionalRules[rule.ID()] && ruleConfig.Enabled == nil {
+			continue
+		}
+		configured, err := ruleConfig.apply(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %s", rule.ID(), err)
+		}
+		if configured != nil {
+			v.rules = append(v.rules, configured)
+		}
+	}
+
+	return v, nil
+}
+
+func (o ConfigOverride) matches(path string) bool {
+	path = filepath.Clean(path)
+	for _, pattern := range o.Paths {
+		if pathMatch(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge returns the rule configuration with the fields set in other
+// replacing its own.
+func (r RuleConfig) merge(other RuleConfig) RuleConfig {
+	if other.Enabled != nil {
+		r.Enabled = other.Enabled
+	}
+	if other.Severity != "" {
+		r.Severity = other.Severity
+	}
+	if other.Options != nil {
+		r.Options = other.Options
+	}
+	return r
+}
+
+// apply returns the rule as configured, or nil if it is disabled.
+func (r RuleConfig) apply(rule Rule) (Rule, error) {
+	if r.Enabled != nil && !*r.Enabled {
+		return nil, nil
+	}
+
+	if r.Options != nil {
+		configurable, ok := rule.(configurableRule)
+		if !ok {
+			return nil, errors.New("rule has no options")
+		}
+		configured, err := configurable.Configure(r.Options)
+		if err != nil {
+			return nil, err
+		}
+		rule = configured
+	}
+
+	if r.Severity != "" {
+		severity, err := ParseSeverity(r.Severity)
+		if err != nil {
+			return nil, err
+		}
+		rule = severityRule{Rule: rule, severity: severity}
+	}
+
+	return rule, nil
+}
+
+// optionStrings returns a rule option holding a list of strings.
+func optionStrings(options map[string]interface{}, key string) ([]string, error) {
+	value, ok := options[key]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("option " + key + " is not a list")
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, errors.New("option " + key + " contains non-string value")
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// checkOptions rejects options a rule does not know.
+func checkOptions(options map[string]interface{}, known ...string) error {
+	for key := range options {
+		found := false
+		for _, k := range known {
+			if key == k {
+				found = true
+			}
+		}
+		if !found {
+			return errors.New("unknown option " + key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const configYAML = `
+allowedVersions: ["2024-01-01"]
+rules:
+  wildcard-resource:
+    options:
+      allowedActions: ["ec2:Describe*"]
+overrides:
+  - paths: ["legacy/**"]
+    rules:
+      wildcard-resource:
+        severity: warning
+  - paths: ["sandbox/*.json"]
+    rules:
+      wildcard-resource:
+        enabled: false
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigVerifierFor(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, configYAML), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wildcard := func(actions ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"Version": "2024-01-01",
+			"Statement": []interface{}{
+				map[string]interface{}{"Sid": "Wildcard", "Effect": "Allow", "Action": actions, "Resource": "*"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		path             string
+		document         map[string]interface{}
+		expectedResult   bool
+		expectedFindings int
+	}{
+		{"AllowedActions", "policy.json", wildcard("ec2:DescribeInstances", "EC2:DescribeVolumes"), true, 0},
+		{"NotAllowedActions", "policy.json", wildcard("ec2:DescribeInstances", "ec2:TerminateInstances"), false, 1},
+		{"SeverityOverride", "legacy/roles/policy.json", wildcard("s3:GetObject"), true, 1},
+		{"DisabledRule", "sandbox/policy.json", wildcard("s3:GetObject"), true, 0},
+		{"OverrideNotMatching", "sandbox/nested/policy.json", wildcard("s3:GetObject"), false, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := cfg.Verifier(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := v.verifyPolicyDocument(tc.document, InlinePolicy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != tc.expectedFindings {
+				t.Errorf("Expected %d findings, but got %v", tc.expectedFindings, findings)
+			}
+			if passed(findings) != tc.expectedResult {
+				t.Errorf("Expected result '%t', but got %t", tc.expectedResult, passed(findings))
+			}
+		})
+	}
+}
+
+func TestConfigAllowedVersions(t *testing.T) {
+	document := map[string]interface{}{
+		"Version": "2024-01-01",
+		"Statement": []interface{}{
+			map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"},
+		},
+	}
+
+	_, err := newVerifier().verifyPolicyDocument(document, InlinePolicy)
+	if err == nil || err.Error() != "Version field is not '2012-10-17' neither '2008-10-17'" {
+		t.Errorf("Expected default verifier to reject version, but got %v", err)
+	}
+
+	cfg, err := LoadConfig(writeConfig(t, `{"allowedVersions": ["2024-01-01"]}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := cfg.Verifier("policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.verifyPolicyDocument(document, InlinePolicy); err != nil {
+		t.Errorf("Expected configured version to be accepted, but got %v", err)
+	}
+
+	document["Version"] = "2000-01-01"
+	_, err = v.verifyPolicyDocument(document, InlinePolicy)
+	if err == nil || err.Error() != "Version field is not one of '2012-10-17', '2008-10-17', '2024-01-01'" {
+		t.Errorf("Expected unknown version to be rejected, but got %v", err)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"UnknownField", "rulez: {}"},
+		{"UnknownRule", "rules: {no-such-rule: {enabled: false}}"},
+		{"UnknownSeverity", "rules: {wildcard-resource: {severity: fatal}}"},
+		{"UnknownOption", "rules: {wildcard-resource: {options: {allowedResources: []}}}"},
+		{"UnknownWildcardPolicy", "rules: {wildcard-resource: {options: {wildcard: everything}}}"},
+		{"RuleWithoutOptions", "rules: {wildcard-principal: {options: {allowedActions: []}}}"},
+		{"OverrideWithoutPaths", "overrides: [{rules: {wildcard-resource: {enabled: false}}}]"},
+		{"OverrideUnknownRule", "overrides: [{paths: ['[ab]/*.json'], rules: {no-such-rule: {enabled: false}}}]"},
+		{"OverrideUnknownSeverity", "overrides: [{paths: ['[ab]/*.json'], rules: {wildcard-resource: {severity: fatal}}}]"},
+		{"OverrideUnknownOption", "overrides: [{paths: ['policies/**'], rules: {wildcard-resource: {options: {allowedResources: []}}}}]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadConfig(writeConfig(t, tc.content), ""); err == nil {
+				t.Errorf("Expected non-nil error, got nil")
+			}
+		})
+	}
+}
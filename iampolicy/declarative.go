@@ -0,0 +1,278 @@
+package iampolicy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// declarativeRule is a rule written in YAML or JSON instead of Go. It
+// reports every statement for which all When predicates hold but not all
+// Require predicates do. Severity defaults to error:
+//
+//	id: passrole-requires-service
+//	description: iam:PassRole must be limited to a service
+//	severity: error
+//	when:
+//	  - {path: Effect, equals: Allow}
+//	  - {path: Action, matches: "iam:PassRole"}
+//	require:
+//	  - {path: "Condition.*.iam:PassedToService", exists: true}
+type declarativeRule struct {
+	RuleID          string      `yaml:"id"`
+	RuleDescription string      `yaml:"description"`
+	RuleSeverity    *Severity   `yaml:"severity"`
+	Kinds           []string    `yaml:"kinds"`
+	Message         string      `yaml:"message"`
+	When            []predicate `yaml:"when"`
+	Require         []predicate `yaml:"require"`
+}
+
+// predicate tests the values found at a dot-separated path of a statement,
+// e.g. "Condition.*.aws:SourceVpc", where "*" selects every key. Action and
+// Resource always hold lists, so "Action" selects each action.
+type predicate struct {
+	Path       string  `yaml:"path"`
+	Exists     *bool   `yaml:"exists"`
+	Equals     *string `yaml:"equals"`
+	Matches    string  `yaml:"matches"`
+	NotMatches string  `yaml:"notMatches"`
+}
+
+type declarativeRuleFile struct {
+	Rules []declarativeRule `yaml:"rules"`
+}
+
+func (r declarativeRule) ID() string { return r.RuleID }
+
+func (r declarativeRule) Description() string { return r.RuleDescription }
+
+func (r declarativeRule) Severity() Severity {
+	if r.RuleSeverity == nil {
+		return SeverityError
+	}
+	return *r.RuleSeverity
+}
+
+func (r declarativeRule) Check(policy *Policy) []Finding {
+	if len(r.Kinds) > 0 {
+		applies := false
+		for _, kind := range r.Kinds {
+			if kind == policy.Kind.String() {
+				applies = true
+			}
+		}
+		if !applies {
+			return nil
+		}
+	}
+
+	message := r.Message
+	if message == "" {
+		message = r.RuleDescription
+	}
+
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		view := statementView(statement)
+		if allHold(r.When, view) && !allHold(r.Require, view) {
+			findings = append(findings, newFinding(r, statement, message))
+		}
+	}
+	return findings
+}
+
+func (r declarativeRule) validate() error {
+	if r.RuleID == "" {
+		return errors.New("rule without id")
+	}
+	if len(r.Require) == 0 {
+		return fmt.Errorf("rule %s has no require predicates", r.RuleID)
+	}
+	for _, kind := range r.Kinds {
+		if kind != InlinePolicy.String() && kind != ManagedPolicy.String() && kind != TrustPolicy.String() {
+			return fmt.Errorf("rule %s has unknown policy kind '%s'", r.RuleID, kind)
+		}
+	}
+	for _, p := range append(append([]predicate{}, r.When...), r.Require...) {
+		if p.Path == "" {
+			return fmt.Errorf("rule %s has a predicate without path", r.RuleID)
+		}
+		if p.Exists == nil && p.Equals == nil && p.Matches == "" && p.NotMatches == "" {
+			return fmt.Errorf("rule %s has a predicate on %s without test", r.RuleID, p.Path)
+		}
+	}
+	return nil
+}
+
+// statementView is the statement as a tree predicates are evaluated on.
+func statementView(statement Statement) map[string]interface{} {
+	view := map[string]interface{}{}
+	for key, value := range statement.Raw {
+		view[key] = value
+	}
+	view["Action"] = statement.Action
+	view["Resource"] = statement.Resource
+	return view
+}
+
+func allHold(predicates []predicate, view map[string]interface{}) bool {
+	for _, p := range predicates {
+		if !p.holds(view) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p predicate) holds(view map[string]interface{}) bool {
+	values := resolvePath(view, strings.Split(p.Path, "."))
+	match := globMatch
+	if strings.HasSuffix(p.Path, "Action") {
+		match = actionMatch
+	}
+	// Statement values are patterns themselves: "iam:*" grants iam:PassRole,
+	// so a value matches when either side matches the other.
+	anyMatches := func(pattern string) bool {
+		for _, value := range values {
+			if match(pattern, value) || match(value, pattern) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if p.Exists != nil && (len(values) > 0) != *p.Exists {
+		return false
+	}
+	if p.Equals != nil {
+		found := false
+		for _, value := range values {
+			if value == *p.Equals {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if p.Matches != "" && !anyMatches(p.Matches) {
+		return false
+	}
+	if p.NotMatches != "" && anyMatches(p.NotMatches) {
+		return false
+	}
+	return true
+}
+
+// resolvePath returns the string values found at a path, flattening lists.
+func resolvePath(value interface{}, path []string) []string {
+	if len(path) == 0 {
+		switch v := value.(type) {
+		case string:
+			return []string{v}
+		case bool:
+			return []string{fmt.Sprint(v)}
+		case float64:
+			return []string{fmt.Sprint(v)}
+		case []string:
+			return v
+		case []interface{}:
+			var values []string
+			for _, item := range v {
+				values = append(values, resolvePath(item, nil)...)
+			}
+			return values
+		case map[string]interface{}:
+			// An existing dictionary counts as a value for exists tests.
+			return []string{""}
+		}
+		return nil
+	}
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if path[0] != "*" {
+		child, ok := m[path[0]]
+		if !ok {
+			return nil
+		}
+		return resolvePath(child, path[1:])
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var values []string
+	for _, key := range keys {
+		values = append(values, resolvePath(m[key], path[1:])...)
+	}
+	return values
+}
+
+// loadRulesDir reads every .yaml, .yml and .json file of a directory. A
+// file holds either a single rule or a list of them under "rules".
+func loadRulesDir(dir string) ([]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		fileData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var probe map[string]interface{}
+		if err := yaml.Unmarshal(fileData, &probe); err != nil {
+			return nil, fmt.Errorf("invalid rule file '%s': %s", path, err)
+		}
+		var file declarativeRuleFile
+		if _, ok := probe["rules"]; ok {
+			err = decodeStrict(fileData, &file)
+		} else {
+			file.Rules = []declarativeRule{{}}
+			err = decodeStrict(fileData, &file.Rules[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule file '%s': %s", path, err)
+		}
+
+		for _, rule := range file.Rules {
+			if err := rule.validate(); err != nil {
+				return nil, fmt.Errorf("invalid rule file '%s': %s", path, err)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// decodeStrict decodes YAML or JSON, rejecting unknown fields. An empty
+// document leaves out unchanged.
+func decodeStrict(fileData []byte, out interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(fileData))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
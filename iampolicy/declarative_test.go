@@ -0,0 +1,188 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const passRoleRuleYAML = `
+id: passrole-requires-service
+description: Allow statements with iam:PassRole must have an iam:PassedToService condition
+severity: warning
+kinds: [inline, managed]
+when:
+  - {path: Effect, equals: Allow}
+  - {path: Action, matches: "iam:PassRole"}
+require:
+  - {path: "Condition.*.iam:PassedToService", exists: true}
+`
+
+const ruleListJSON = `{
+  "rules": [
+    {
+      "id": "no-s3-delete",
+      "description": "S3 objects must not be deleted",
+      "message": "statement allows s3:DeleteObject",
+      "when": [{"path": "Effect", "equals": "Allow"}],
+      "require": [{"path": "Action", "notMatches": "s3:DeleteObject"}]
+    }
+  ]
+}`
+
+func writeRules(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDeclarativeRules(t *testing.T) {
+	rules, err := loadRulesDir(writeRules(t, map[string]string{
+		"passrole.yaml": passRoleRuleYAML,
+		"s3.json":       ruleListJSON,
+		"README.md":     "not a rule",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, but got %d", len(rules))
+	}
+
+	testCases := []struct {
+		name      string
+		statement map[string]interface{}
+		expected  []string
+	}{
+		{
+			name:      "PassRoleWithoutCondition",
+			statement: map[string]interface{}{"Effect": "Allow", "Action": "iam:PassRole", "Resource": "arn:aws:iam::123456789012:role/app"},
+			expected:  []string{"passrole-requires-service"},
+		},
+		{
+			name:      "PassRoleThroughWildcard",
+			statement: map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"IAM:*"}, "Resource": "arn:aws:iam::123456789012:role/app"},
+			expected:  []string{"passrole-requires-service"},
+		},
+		{
+			name: "PassRoleWithCondition",
+			statement: map[string]interface{}{
+				"Effect":    "Allow",
+				"Action":    "iam:PassRole",
+				"Resource":  "arn:aws:iam::123456789012:role/app",
+				"Condition": map[string]interface{}{"StringEquals": map[string]interface{}{"iam:PassedToService": "ec2.amazonaws.com"}},
+			},
+			expected: nil,
+		},
+		{
+			name:      "DenyPassRole",
+			statement: map[string]interface{}{"Effect": "Deny", "Action": "iam:PassRole", "Resource": "arn:aws:iam::123456789012:role/app"},
+			expected:  nil,
+		},
+		{
+			name:      "S3Wildcard",
+			statement: map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"s3:Get*", "s3:*"}, "Resource": "arn:aws:s3:::bucket/*"},
+			expected:  []string{"no-s3-delete"},
+		},
+	}
+
+	v := &Verifier{rules: rules}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, err := v.verifyPolicyDocument(map[string]interface{}{
+				"Version":   "2012-10-17",
+				"Statement": []interface{}{tc.statement},
+			}, InlinePolicy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != len(tc.expected) {
+				t.Fatalf("Expected findings of %v, but got %v", tc.expected, findings)
+			}
+			for i, finding := range findings {
+				if finding.RuleID != tc.expected[i] {
+					t.Errorf("Expected finding of %s, but got %s", tc.expected[i], finding.RuleID)
+				}
+			}
+		})
+	}
+
+	if rules[0].Severity() != SeverityWarning || rules[1].Severity() != SeverityError {
+		t.Errorf("Expected severities warning and error, but got %s and %s", rules[0].Severity(), rules[1].Severity())
+	}
+	if finding := rules[1].Check(parsePolicy(map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": []interface{}{map[string]interface{}{"Effect": "Allow", "Action": "s3:DeleteObject", "Resource": "*"}},
+	}, InlinePolicy)); len(finding) != 1 || finding[0].Message != "statement allows s3:DeleteObject" {
+		t.Errorf("Expected finding with rule message, but got %v", finding)
+	}
+}
+
+func TestLoadRulesDirErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"MissingID", "require: [{path: Sid, exists: true}]"},
+		{"MissingRequire", "id: x"},
+		{"UnknownField", "id: x\nrequire: [{path: Sid, exists: true}]\nseverty: error"},
+		{"UnknownSeverity", "id: x\nseverity: fatal\nrequire: [{path: Sid, exists: true}]"},
+		{"UnknownKind", "id: x\nkinds: [bucket]\nrequire: [{path: Sid, exists: true}]"},
+		{"PredicateWithoutTest", "id: x\nrequire: [{path: Sid}]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := loadRulesDir(writeRules(t, map[string]string{"rule.yaml": tc.content})); err == nil {
+				t.Errorf("Expected non-nil error, got nil")
+			}
+		})
+	}
+}
+
+func TestConfigRulesDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "rules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "rules", "passrole.yaml"), []byte(passRoleRuleYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configFile, []byte("rulesDir: rules\nrules:\n  passrole-requires-service:\n    severity: error\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Declarative rules belong to the configuration, so loading it again,
+	// as every Verify call with WithConfigFile does, must not conflict.
+	for i := 0; i < 2; i++ {
+		cfg, err := LoadConfig(configFile, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := cfg.Verifier("policy.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		findings, err := v.verifyPolicyDocument(map[string]interface{}{
+			"Version":   "2012-10-17",
+			"Statement": []interface{}{map[string]interface{}{"Effect": "Allow", "Action": "iam:PassRole", "Resource": "arn:aws:iam::123456789012:role/app"}},
+		}, InlinePolicy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if passed(findings) {
+			t.Errorf("Expected configured error severity to fail verification, but got %v", findings)
+		}
+	}
+	if _, ok := LookupRule("passrole-requires-service"); ok {
+		t.Error("Expected declarative rules not to be registered globally")
+	}
+	if _, err := LoadConfig(configFile, filepath.Join(dir, "rules")); err == nil {
+		t.Error("Expected a rule declared twice to be rejected")
+	}
+}
@@ -0,0 +1,170 @@
+package iampolicy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Grant is one action on one resource pattern allowed or denied by a
+// statement, under the statement's principal and condition.
+type Grant struct {
+	Effect    string
+	Action    string
+	Resource  string
+	statement Statement
+	principal string
+}
+
+// policyGrants breaks a policy down into grants, expanding action wildcards
+// into the catalog actions they match. Wildcards of services the catalog
+// does not cover are kept as they are. Statements using NotAction or
+// NotResource are left out; see Statement.UsesNegation.
+func policyGrants(policy *Policy, catalog *Catalog) []Grant {
+	var grants []Grant
+	for _, statement := range policy.Statements {
+		if statement.UsesNegation() {
+			continue
+		}
+		principal := string(minifiedJSON(normalizeStatement(statement.Raw)["Principal"]))
+		resources := statement.Resource
+		if len(resources) == 0 {
+			resources = []string{""}
+		}
+		for _, action := range expandActions(statement.Action, catalog) {
+			for _, resource := range resources {
+				grants = append(grants, Grant{Effect: statement.Effect, Action: action, Resource: resource, statement: statement, principal: principal})
+			}
+		}
+	}
+	return grants
+}
+
+func expandActions(actions []string, catalog *Catalog) []string {
+	var expanded []string
+	seen := map[string]bool{}
+	for _, action := range actions {
+		list := []string{action}
+		if strings.ContainsAny(action, "*?") && catalog.covers(action) {
+			list = catalog.expand(action)
+		}
+		for _, a := range list {
+			if !seen[strings.ToLower(a)] {
+				seen[strings.ToLower(a)] = true
+				expanded = append(expanded, a)
+			}
+		}
+	}
+	return expanded
+}
+
+// covered reports whether a grant of the same principal in grants matches
+// every request g matches. A grant whose condition is a subset of g's
+// condition applies whenever g does.
+func (g Grant) covered(grants []Grant) bool {
+	for _, other := range grants {
+		if other.principal == g.principal && conditionWithin(other.statement.Condition, g.statement.Condition) &&
+			actionSubsumes(other.Action, g.Action) && globSubsumes(other.Resource, g.Resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionWithin reports whether every operator and key of condition is
+// also tested, with the same values, by other.
+func conditionWithin(condition, other map[string]interface{}) bool {
+	for operator, keys := range condition {
+		keyMap, ok := keys.(map[string]interface{})
+		otherKeys, _ := other[operator].(map[string]interface{})
+		if !ok || otherKeys == nil {
+			return false
+		}
+		for key, value := range keyMap {
+			otherValue, ok := otherKeys[key]
+			if !ok || !bytes.Equal(minifiedJSON(sortedStrings(value)), minifiedJSON(sortedStrings(otherValue))) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitGrants separates the Allow grants from the Deny ones.
+func splitGrants(grants []Grant) (allows, denies []Grant) {
+	for _, g := range grants {
+		if g.Effect == "Deny" {
+			denies = append(denies, g)
+		} else {
+			allows = append(allows, g)
+		}
+	}
+	return allows, denies
+}
+
+// Diff returns the access after adds to before, and the access it
+// removes, one action at a time with the wildcards of services the catalog
+// covers expanded. It compares effective allows: Allow grants a Deny of the
+// same policy covers grant nothing. A Deny only in after removes access and
+// a Deny only in before adds it back, so those are returned as removed and
+// added respectively.
+func Diff(before, after *Policy, catalog *Catalog) (added, removed []Grant) {
+	beforeAllows, beforeDenies := splitGrants(policyGrants(before, catalog))
+	afterAllows, afterDenies := splitGrants(policyGrants(after, catalog))
+	for _, g := range afterAllows {
+		if !g.covered(beforeAllows) && !g.covered(afterDenies) {
+			added = append(added, g)
+		}
+	}
+	for _, g := range beforeDenies {
+		if !g.covered(afterDenies) {
+			added = append(added, g)
+		}
+	}
+	for _, g := range beforeAllows {
+		if !g.covered(afterAllows) && !g.covered(beforeDenies) {
+			removed = append(removed, g)
+		}
+	}
+	for _, g := range afterDenies {
+		if !g.covered(beforeDenies) {
+			removed = append(removed, g)
+		}
+	}
+	return added, removed
+}
+
+// FormatGrants describes grants one action per line, listing the resources
+// of grants sharing effect, principal, condition and action together.
+func FormatGrants(grants []Grant) []string {
+	var keys []string
+	resources := map[string][]string{}
+	first := map[string]Grant{}
+	for _, g := range grants {
+		key := g.Effect + "\x00" + g.principal + "\x00" + string(minifiedJSON(g.statement.Condition)) + "\x00" + strings.ToLower(g.Action)
+		if _, ok := first[key]; !ok {
+			keys = append(keys, key)
+			first[key] = g
+		}
+		if g.Resource != "" {
+			resources[key] = append(resources[key], g.Resource)
+		}
+	}
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		g := first[key]
+		line := fmt.Sprintf("%s %s", g.Effect, g.Action)
+		if len(resources[key]) > 0 {
+			line += " on " + strings.Join(sortedUnique(resources[key]), ", ")
+		}
+		if g.statement.Principal != nil {
+			line += fmt.Sprintf(" for %s", g.principal)
+		}
+		if g.statement.Condition != nil {
+			line += fmt.Sprintf(" when %s", minifiedJSON(g.statement.Condition))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
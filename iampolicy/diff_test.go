@@ -0,0 +1,109 @@
+package iampolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPolicies(t *testing.T) {
+	catalog, err := parseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secure := map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}
+	testCases := []struct {
+		name            string
+		before          []interface{}
+		after           []interface{}
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{
+			name: "Reordered",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"sqs:SendMessage", "s3:GetObject"}, "Resource": "*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"},
+				map[string]interface{}{"Effect": "Allow", "Action": "SQS:sendmessage", "Resource": "*"},
+			},
+		},
+		{
+			name: "WildcardExpanded",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "arn:aws:sqs:us-east-1:1:q"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "sqs:Send*", "Resource": "arn:aws:sqs:us-east-1:1:q"},
+			},
+			expectedAdded: []string{"Allow sqs:SendMessageBatch on arn:aws:sqs:us-east-1:1:q"},
+		},
+		{
+			name: "ResourceNarrowed",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": []interface{}{"arn:aws:s3:::b/logs/*", "arn:aws:s3:::c/*"}},
+			},
+			expectedAdded:   []string{"Allow s3:GetObject on arn:aws:s3:::c/*"},
+			expectedRemoved: []string{"Allow s3:GetObject on arn:aws:s3:::b/*"},
+		},
+		{
+			name: "ConditionAdded",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*", "Condition": secure},
+			},
+			expectedRemoved: []string{"Allow s3:PutObject on arn:aws:s3:::b/*"},
+		},
+		{
+			name: "DenyRemoved",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::b/*"},
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::b/*"},
+			},
+			expectedAdded: []string{"Deny s3:PutObject on arn:aws:s3:::b/*"},
+		},
+		{
+			name: "DenyAdded",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::b/*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::b/*"},
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::b/*"},
+			},
+			expectedRemoved: []string{"Deny s3:DeleteObject on arn:aws:s3:::b/*"},
+		},
+		{
+			name: "AllowAddedUnderDeny",
+			before: []interface{}{
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "*"},
+			},
+			after: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::b/*"},
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "*"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": tc.before}, InlinePolicy)
+			after := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": tc.after}, InlinePolicy)
+			added, removed := Diff(before, after, catalog)
+			if res := FormatGrants(added); !reflect.DeepEqual(res, tc.expectedAdded) && len(res)+len(tc.expectedAdded) > 0 {
+				t.Errorf("Expected added %q, but got %q", tc.expectedAdded, res)
+			}
+			if res := FormatGrants(removed); !reflect.DeepEqual(res, tc.expectedRemoved) && len(res)+len(tc.expectedRemoved) > 0 {
+				t.Errorf("Expected removed %q, but got %q", tc.expectedRemoved, res)
+			}
+		})
+	}
+}
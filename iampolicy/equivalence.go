@@ -0,0 +1,500 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Relation is how the access granted by one policy compares to another's.
+type Relation int
+
+const (
+	Equivalent Relation = iota
+	Subset
+	Superset
+	Incomparable
+)
+
+func (r Relation) String() string {
+	switch r {
+	case Equivalent:
+		return "equivalent"
+	case Subset:
+		return "subset"
+	case Superset:
+		return "superset"
+	default:
+		return "incomparable"
+	}
+}
+
+// AccessRequest is a request one policy allows and the other does not.
+// Conditions gives the value assumed for every condition of either policy,
+// keyed by "operator key values".
+type AccessRequest struct {
+	Principal  string          `json:"principal,omitempty"`
+	Action     string          `json:"action"`
+	Resource   string          `json:"resource"`
+	Conditions map[string]bool `json:"conditions,omitempty"`
+}
+
+// Comparison is the result of comparing policy A with policy B. OnlyA is a
+// request allowed by A but not by B, OnlyB one allowed by B but not by A.
+type Comparison struct {
+	Relation Relation
+	OnlyA    *AccessRequest
+	OnlyB    *AccessRequest
+}
+
+// Limits keeping the comparison of unusually large policies bounded.
+const (
+	maxRegionStates = 100000
+	maxConditions   = 12
+)
+
+// Compare decides whether policy a grants a subset of, a superset
+// of, or the same access as policy b.
+//
+// A request is allowed when an Allow statement matches it and no Deny
+// statement does. Action, Resource and principal patterns are compared
+// exactly: every combination of patterns some value matches is explored.
+// With a catalog, the actions it lists are taken to be the only actions of
+// their services, so "sqs:Send*" equals "sqs:SendMessage" and
+// "sqs:SendMessageBatch"; without one it also matches actions yet to come.
+// Condition blocks are split into their
+// operator, key and values; each distinct one is treated as an independent
+// fact that may or may not hold, so conditions written differently but
+// meaning the same are reported as different.
+func Compare(a, b *Policy, catalog *Catalog) (Comparison, error) {
+	c := newPolicyComparer()
+	statements := [2][]compiledStatement{c.compile(a), c.compile(b)}
+	if len(c.conditions) > maxConditions {
+		return Comparison{}, fmt.Errorf("policies use %d distinct conditions, more than the %d that can be compared", len(c.conditions), maxConditions)
+	}
+
+	var actions, resources, principals []region
+	var err error
+	if catalog == nil {
+		actions, err = c.actions.regions()
+	} else {
+		actions, err = c.catalogRegions(catalog)
+	}
+	if err != nil {
+		return Comparison{}, err
+	}
+	if resources, err = c.resources.regions(); err != nil {
+		return Comparison{}, err
+	}
+	if principals, err = c.principals.regions(); err != nil {
+		return Comparison{}, err
+	}
+
+	var result Comparison
+	conditions := make([]bool, len(c.conditions))
+	// Assignments are tried from all conditions holding to none, so that
+	// counterexamples assume as few false conditions as possible.
+	for mask := 0; mask < 1<<len(c.conditions); mask++ {
+		for i := range conditions {
+			conditions[i] = mask&(1<<i) == 0
+		}
+		for _, principal := range principals {
+			for _, action := range actions {
+				for _, resource := range resources {
+					point := requestPoint{action.matches, resource.matches, principal.matches, conditions}
+					allowedA := allowed(statements[0], point)
+					allowedB := allowed(statements[1], point)
+					if allowedA == allowedB {
+						continue
+					}
+					request := c.request(action.witness, resource.witness, principal.witness, conditions)
+					if allowedA && result.OnlyA == nil {
+						result.OnlyA = request
+					}
+					if allowedB && result.OnlyB == nil {
+						result.OnlyB = request
+					}
+					if result.OnlyA != nil && result.OnlyB != nil {
+						result.Relation = Incomparable
+						return result, nil
+					}
+				}
+			}
+		}
+	}
+
+	switch {
+	case result.OnlyA != nil:
+		result.Relation = Superset
+	case result.OnlyB != nil:
+		result.Relation = Subset
+	default:
+		result.Relation = Equivalent
+	}
+	return result, nil
+}
+
+// compiledStatement refers to the patterns and conditions of a statement by
+// their index in the comparer's pattern sets.
+type compiledStatement struct {
+	allow       bool
+	actions     []int
+	notAction   bool
+	resources   []int
+	notResource bool
+	anyResource bool
+	principals  []int
+	anyOne      bool
+	conditions  []int
+}
+
+type policyComparer struct {
+	actions    *patternSet
+	resources  *patternSet
+	principals *patternSet
+	conditions []string
+	condition  map[string]int
+}
+
+func newPolicyComparer() *policyComparer {
+	return &policyComparer{
+		actions:    newPatternSet(true),
+		resources:  newPatternSet(false),
+		principals: newPatternSet(false),
+		condition:  map[string]int{},
+	}
+}
+
+func (c *policyComparer) compile(policy *Policy) []compiledStatement {
+	var compiled []compiledStatement
+	for _, statement := range policy.Statements {
+		s := compiledStatement{allow: statement.Effect == "Allow"}
+
+		actions := statement.Action
+		if statement.Raw["NotAction"] != nil {
+			actions, s.notAction = statement.NotAction, true
+		}
+		for _, action := range actions {
+			s.actions = append(s.actions, c.actions.add(action))
+		}
+
+		resources := statement.Resource
+		if statement.Raw["NotResource"] != nil {
+			resources, s.notResource = statement.NotResource, true
+		}
+		s.anyResource = statement.Raw["Resource"] == nil && statement.Raw["NotResource"] == nil
+		for _, resource := range resources {
+			s.resources = append(s.resources, c.resources.add(resource))
+		}
+
+		s.anyOne = statement.Principal == nil
+		for _, principal := range principalPatterns(statement.Principal) {
+			s.principals = append(s.principals, c.principals.add(principal))
+		}
+
+		for _, condition := range conditionAtoms(statement.Condition) {
+			index, ok := c.condition[condition]
+			if !ok {
+				index = len(c.conditions)
+				c.condition[condition] = index
+				c.conditions = append(c.conditions, condition)
+			}
+			s.conditions = append(s.conditions, index)
+		}
+		compiled = append(compiled, s)
+	}
+	return compiled
+}
+
+// catalogRegions returns the action regions outside the complete services
+// of the catalog, and one region per catalog action inside them.
+func (c *policyComparer) catalogRegions(catalog *Catalog) ([]region, error) {
+	extended := newPatternSet(true)
+	for _, pattern := range c.actions.patterns {
+		extended.add(pattern)
+	}
+	var services []int
+	for service := range catalog.complete {
+		services = append(services, extended.add(service+":*"))
+	}
+	all, err := extended.regions()
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := len(c.actions.patterns)
+	var regions []region
+	for _, r := range all {
+		if !anyIndex(services, r.matches) {
+			regions = append(regions, region{matches: r.matches[:patterns], witness: r.witness})
+		}
+	}
+	for _, action := range catalog.actions {
+		if !catalog.covers(action) {
+			continue
+		}
+		matches := make([]bool, patterns)
+		for i, pattern := range c.actions.patterns {
+			matches[i] = globMatch(pattern, strings.ToLower(action))
+		}
+		regions = append(regions, region{matches: matches, witness: action})
+	}
+	return regions, nil
+}
+
+func (c *policyComparer) request(action, resource, principal string, conditions []bool) *AccessRequest {
+	request := &AccessRequest{Principal: principal, Action: action, Resource: resource}
+	if len(conditions) > 0 {
+		request.Conditions = map[string]bool{}
+		for i, holds := range conditions {
+			request.Conditions[c.conditions[i]] = holds
+		}
+	}
+	return request
+}
+
+// principalPatterns returns the principals of a statement as "type:value"
+// patterns, with "*" standing for every principal.
+func principalPatterns(principal interface{}) []string {
+	switch p := principal.(type) {
+	case string:
+		return []string{p}
+	case map[string]interface{}:
+		var patterns []string
+		for kind, values := range p {
+			for _, value := range stringList(values) {
+				patterns = append(patterns, kind+":"+value)
+			}
+		}
+		sort.Strings(patterns)
+		return patterns
+	}
+	return nil
+}
+
+// conditionAtoms splits a Condition block into "operator key values"
+// strings, one per key; the block holds when all of them do.
+func conditionAtoms(condition map[string]interface{}) []string {
+	var atoms []string
+	for operator, keys := range condition {
+		keyMap, ok := keys.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range keyMap {
+			atoms = append(atoms, fmt.Sprintf("%s %s %s", operator, key, minifiedJSON(sortedStrings(value))))
+		}
+	}
+	sort.Strings(atoms)
+	return atoms
+}
+
+// requestPoint describes a class of requests by the patterns its action,
+// resource and principal match and the conditions that hold.
+type requestPoint struct {
+	actions    []bool
+	resources  []bool
+	principals []bool
+	conditions []bool
+}
+
+func allowed(statements []compiledStatement, point requestPoint) bool {
+	allow := false
+	for _, s := range statements {
+		if !s.matches(point) {
+			continue
+		}
+		if !s.allow {
+			return false
+		}
+		allow = true
+	}
+	return allow
+}
+
+func (s compiledStatement) matches(point requestPoint) bool {
+	if anyIndex(s.actions, point.actions) == s.notAction {
+		return false
+	}
+	if !s.anyResource && anyIndex(s.resources, point.resources) == s.notResource {
+		return false
+	}
+	if !s.anyOne && !anyIndex(s.principals, point.principals) {
+		return false
+	}
+	for _, i := range s.conditions {
+		if !point.conditions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func anyIndex(indexes []int, matches []bool) bool {
+	for _, i := range indexes {
+		if matches[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// patternSet collects the glob patterns used for one part of a request.
+type patternSet struct {
+	patterns []string
+	index    map[string]int
+	fold     bool
+}
+
+func newPatternSet(fold bool) *patternSet {
+	return &patternSet{index: map[string]int{}, fold: fold}
+}
+
+func (s *patternSet) add(pattern string) int {
+	if s.fold {
+		pattern = strings.ToLower(pattern)
+	}
+	if i, ok := s.index[pattern]; ok {
+		return i
+	}
+	s.index[pattern] = len(s.patterns)
+	s.patterns = append(s.patterns, pattern)
+	return len(s.patterns) - 1
+}
+
+// region is a combination of patterns some value matches, all others not,
+// with the shortest such value as witness.
+type region struct {
+	matches []bool
+	witness string
+}
+
+// regions returns every combination of patterns that some value matches.
+// It runs all patterns in parallel over the characters they use plus one
+// they do not, breadth first, so each region's witness is shortest.
+func (s *patternSet) regions() ([]region, error) {
+	used := map[byte]bool{}
+	for _, pattern := range s.patterns {
+		for i := 0; i < len(pattern); i++ {
+			if pattern[i] != '*' && pattern[i] != '?' {
+				used[pattern[i]] = true
+			}
+		}
+	}
+	var alphabet []byte
+	for c := range used {
+		alphabet = append(alphabet, c)
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	for _, c := range []byte("xyz0123456789-_~") {
+		if !used[c] {
+			alphabet = append(alphabet, c)
+			break
+		}
+	}
+
+	type state struct {
+		positions [][]int
+		witness   string
+	}
+	start := state{positions: make([][]int, len(s.patterns))}
+	for i, pattern := range s.patterns {
+		start.positions[i] = globClosure(pattern, []int{0})
+	}
+
+	var regions []region
+	seenRegions := map[string]bool{}
+	record := func(positions [][]int, witness string) {
+		matches := make([]bool, len(s.patterns))
+		for i, pattern := range s.patterns {
+			for _, p := range positions[i] {
+				if p == len(pattern) {
+					matches[i] = true
+				}
+			}
+		}
+		if key := fmt.Sprint(matches); !seenRegions[key] {
+			seenRegions[key] = true
+			regions = append(regions, region{matches: matches, witness: witness})
+		}
+	}
+	seenStates := map[string]bool{stateKey(start.positions): true}
+	queue := []state{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		// The empty value is a witness of last resort; an empty action or
+		// resource makes for a confusing counterexample.
+		if current.witness != "" {
+			record(current.positions, current.witness)
+		}
+
+		for _, c := range alphabet {
+			next := state{positions: make([][]int, len(s.patterns)), witness: current.witness + string(c)}
+			for i, pattern := range s.patterns {
+				next.positions[i] = globStep(pattern, current.positions[i], c)
+			}
+			key := stateKey(next.positions)
+			if seenStates[key] {
+				continue
+			}
+			if len(seenStates) >= maxRegionStates {
+				return nil, errors.New("patterns are too complex to compare")
+			}
+			seenStates[key] = true
+			queue = append(queue, next)
+		}
+	}
+	record(start.positions, "")
+	return regions, nil
+}
+
+// globClosure adds the positions reachable by letting "*" match nothing.
+func globClosure(pattern string, positions []int) []int {
+	seen := map[int]bool{}
+	var closed []int
+	for _, p := range positions {
+		for !seen[p] {
+			seen[p] = true
+			closed = append(closed, p)
+			if p >= len(pattern) || pattern[p] != '*' {
+				break
+			}
+			p++
+		}
+	}
+	sort.Ints(closed)
+	return closed
+}
+
+// globStep returns the positions of pattern reachable by consuming c.
+func globStep(pattern string, positions []int, c byte) []int {
+	var next []int
+	for _, p := range positions {
+		if p >= len(pattern) {
+			continue
+		}
+		switch pattern[p] {
+		case '*':
+			next = append(next, p)
+		case '?':
+			next = append(next, p+1)
+		case c:
+			next = append(next, p+1)
+		}
+	}
+	return globClosure(pattern, next)
+}
+
+func stateKey(positions [][]int) string {
+	var b strings.Builder
+	for _, list := range positions {
+		for _, p := range list {
+			fmt.Fprintf(&b, "%d,", p)
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package iampolicy
+
+import (
+	"testing"
+)
+
+func TestComparePolicies(t *testing.T) {
+	secure := map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}
+	testCases := []struct {
+		name     string
+		a        []interface{}
+		b        []interface{}
+		kind     PolicyKind
+		catalog  bool
+		expected Relation
+	}{
+		{
+			name: "Reordered",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"s3:GetObject", "sqs:SendMessage"}, "Resource": []interface{}{"arn:aws:s3:::b/*", "arn:aws:s3:::c/*"}},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"SQS:sendmessage", "s3:GetObject"}, "Resource": "arn:aws:s3:::c/*"},
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"s3:GetObject", "sqs:SendMessage"}, "Resource": "arn:aws:s3:::b/*"},
+			},
+			expected: Equivalent,
+		},
+		{
+			name: "NarrowerResource",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/logs/*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:Get*", "Resource": "arn:aws:s3:::b/*"},
+			},
+			expected: Subset,
+		},
+		{
+			name: "DenyCarvesOut",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "*"},
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:Delete*", "Resource": "*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "NotAction": "s3:Delete*", "Resource": "*"},
+			},
+			expected: Subset,
+		},
+		{
+			name: "NotResource",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "NotResource": "arn:aws:s3:::secret/*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"},
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::secret/*"},
+			},
+			expected: Equivalent,
+		},
+		{
+			name: "ConditionNarrows",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "*", "Condition": secure},
+			},
+			expected: Superset,
+		},
+		{
+			name: "Incomparable",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "*"},
+			},
+			expected: Incomparable,
+		},
+		{
+			name: "WildcardWithoutCatalog",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"sqs:SendMessage", "sqs:SendMessageBatch"}, "Resource": "*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "sqs:Send*", "Resource": "*"},
+			},
+			expected: Subset,
+		},
+		{
+			name: "WildcardWithCatalog",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"sqs:SendMessage", "sqs:SendMessageBatch", "ec2:DescribeInstances"}, "Resource": "*"},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"sqs:Send*", "ec2:DescribeInstances"}, "Resource": "*"},
+			},
+			catalog:  true,
+			expected: Equivalent,
+		},
+		{
+			name: "TrustPrincipals",
+			a: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"Service": []interface{}{"ec2.amazonaws.com", "lambda.amazonaws.com"}}},
+			},
+			b: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"Service": "ec2.amazonaws.com"}},
+			},
+			kind:     TrustPolicy,
+			expected: Superset,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var catalog *Catalog
+			if tc.catalog {
+				var err error
+				if catalog, err = parseCatalog([]byte(testCatalog)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": tc.a}, tc.kind)
+			b := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": tc.b}, tc.kind)
+			result, err := Compare(a, b, catalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Relation != tc.expected {
+				t.Fatalf("Expected %s, but got %s (only A: %+v, only B: %+v)", tc.expected, result.Relation, result.OnlyA, result.OnlyB)
+			}
+			if (result.OnlyA != nil) != (tc.expected == Superset || tc.expected == Incomparable) {
+				t.Errorf("Unexpected counterexample allowed only by A: %+v", result.OnlyA)
+			}
+			if (result.OnlyB != nil) != (tc.expected == Subset || tc.expected == Incomparable) {
+				t.Errorf("Unexpected counterexample allowed only by B: %+v", result.OnlyB)
+			}
+
+			// Counterexamples must really be allowed by one policy only.
+			reverse, err := Compare(b, a, catalog)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (reverse.OnlyA == nil) != (result.OnlyB == nil) || (reverse.OnlyB == nil) != (result.OnlyA == nil) {
+				t.Errorf("Expected comparison to be symmetric, got %s and %s", result.Relation, reverse.Relation)
+			}
+		})
+	}
+}
+
+func TestCompareCounterexample(t *testing.T) {
+	a := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"},
+	}}, InlinePolicy)
+	b := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/logs/*"},
+	}}, InlinePolicy)
+
+	result, err := Compare(a, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := result.OnlyA
+	if result.Relation != Superset || request == nil {
+		t.Fatalf("Expected superset with counterexample, got %s", result.Relation)
+	}
+	if request.Action != "s3:getobject" || !globMatch("arn:aws:s3:::b/*", request.Resource) || globMatch("arn:aws:s3:::b/logs/*", request.Resource) {
+		t.Errorf("Counterexample %+v is not allowed by A only", request)
+	}
+}
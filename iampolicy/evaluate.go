@@ -0,0 +1,363 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decision is the outcome of evaluating a request against a policy, named
+// as by the IAM policy simulator.
+type Decision int
+
+const (
+	ImplicitDeny Decision = iota
+	ExplicitDeny
+	Allowed
+)
+
+func (d Decision) String() string {
+	switch d {
+	case ExplicitDeny:
+		return "explicitDeny"
+	case Allowed:
+		return "allowed"
+	default:
+		return "implicitDeny"
+	}
+}
+
+func (d Decision) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Request is a simulated request. Principal is given as
+// "Type:value", e.g. "Service:ec2.amazonaws.com", and only matters for
+// trust policies; Resource only matters for permission policies. Context
+// holds the values of condition keys, single values or lists.
+type Request struct {
+	Principal string                 `json:"principal,omitempty"`
+	Action    string                 `json:"action"`
+	Resource  string                 `json:"resource,omitempty"`
+	Context   map[string]interface{} `json:"context,omitempty"`
+}
+
+// Evaluation tells whether a policy allows a request and which statements
+// allowed or denied it.
+type Evaluation struct {
+	Decision  Decision `json:"decision"`
+	AllowedBy []string `json:"allowedBy,omitempty"`
+	DeniedBy  []string `json:"deniedBy,omitempty"`
+}
+
+// Evaluate evaluates a request against a single policy: an explicit
+// Deny overrides any Allow, and a request no statement allows is implicitly
+// denied. It fails on condition operators it does not know.
+func Evaluate(policy *Policy, request Request) (Evaluation, error) {
+	if request.Action == "" {
+		return Evaluation{}, errors.New("request has no action")
+	}
+	context := map[string][]string{}
+	for key, value := range request.Context {
+		context[strings.ToLower(key)] = conditionValues(value)
+	}
+
+	var result Evaluation
+	for _, statement := range policy.Statements {
+		applies, err := statementApplies(statement, policy.Kind, request, context)
+		if err != nil {
+			return Evaluation{}, fmt.Errorf("statement %s: %s", statement.Name(), err)
+		}
+		if !applies {
+			continue
+		}
+		if statement.Effect == "Deny" {
+			result.DeniedBy = append(result.DeniedBy, statement.Name())
+		} else {
+			result.AllowedBy = append(result.AllowedBy, statement.Name())
+		}
+	}
+
+	switch {
+	case len(result.DeniedBy) > 0:
+		result.Decision = ExplicitDeny
+	case len(result.AllowedBy) > 0:
+		result.Decision = Allowed
+	}
+	return result, nil
+}
+
+func statementApplies(statement Statement, kind PolicyKind, request Request, context map[string][]string) (bool, error) {
+	if statement.Raw["NotAction"] != nil {
+		if anyMatch(statement.NotAction, request.Action, actionMatch) {
+			return false, nil
+		}
+	} else if !anyMatch(statement.Action, request.Action, actionMatch) {
+		return false, nil
+	}
+
+	if kind == TrustPolicy {
+		if principal, ok := statement.Raw["NotPrincipal"]; ok {
+			if principalMatches(principal, request.Principal) {
+				return false, nil
+			}
+		} else if !principalMatches(statement.Principal, request.Principal) {
+			return false, nil
+		}
+	} else if statement.Raw["NotResource"] != nil {
+		if anyMatch(statement.NotResource, request.Resource, globMatch) {
+			return false, nil
+		}
+	} else if !anyMatch(statement.Resource, request.Resource, globMatch) {
+		return false, nil
+	}
+
+	return conditionHolds(statement.Condition, context)
+}
+
+func anyMatch(patterns []string, value string, match func(pattern, value string) bool) bool {
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalMatches reports whether a Principal element names the principal
+// of a request. An AWS account, given by its ID or root ARN, names every
+// principal of the account.
+func principalMatches(principal interface{}, requested string) bool {
+	kind, value, _ := strings.Cut(requested, ":")
+	for _, pattern := range principalPatterns(principal) {
+		if pattern == "*" || pattern == "AWS:*" {
+			return true
+		}
+		patternKind, patternValue, _ := strings.Cut(pattern, ":")
+		if !strings.EqualFold(patternKind, kind) {
+			continue
+		}
+		if globMatch(patternValue, value) {
+			return true
+		}
+		if kind == "AWS" && principalAccount(patternValue) != "" && principalAccount(patternValue) == principalAccount(value) &&
+			(!strings.HasPrefix(patternValue, "arn:") || strings.HasSuffix(patternValue, ":root")) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalAccount returns the account of an AWS principal given by account
+// ID or ARN.
+func principalAccount(principal string) string {
+	if len(principal) == 12 && strings.Trim(principal, "0123456789") == "" {
+		return principal
+	}
+	fields := strings.Split(principal, ":")
+	if len(fields) >= 6 && fields[0] == "arn" {
+		return fields[4]
+	}
+	return ""
+}
+
+// conditionValues turns a policy or context value into its string forms.
+func conditionValues(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, conditionValues(item)...)
+		}
+		return values
+	case []string:
+		return v
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// conditionHolds evaluates a Condition block; it holds when every key of
+// every operator does.
+func conditionHolds(condition map[string]interface{}, context map[string][]string) (bool, error) {
+	operators := make([]string, 0, len(condition))
+	for operator := range condition {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+
+	for _, operator := range operators {
+		keys, ok := condition[operator].(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("condition operator %s does not hold a dictionary", operator)
+		}
+		for key, value := range keys {
+			holds, err := conditionKeyHolds(operator, conditionValues(value), context[strings.ToLower(key)])
+			if err != nil {
+				return false, err
+			}
+			if !holds {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// conditionKeyHolds evaluates one key of a condition operator, with its
+// ForAnyValue/ForAllValues prefix and IfExists suffix, against the request
+// values of the key.
+func conditionKeyHolds(operator string, values, requested []string) (bool, error) {
+	base := operator
+	set := ""
+	if prefix, rest, ok := strings.Cut(base, ":"); ok {
+		if prefix != "ForAnyValue" && prefix != "ForAllValues" {
+			return false, fmt.Errorf("unknown condition operator %s", operator)
+		}
+		set, base = prefix, rest
+	}
+	ifExists := strings.HasSuffix(base, "IfExists") && base != "Null"
+	base = strings.TrimSuffix(base, "IfExists")
+
+	if base == "Null" {
+		if len(values) != 1 || (values[0] != "true" && values[0] != "false") {
+			return false, errors.New("condition operator Null takes 'true' or 'false'")
+		}
+		return (len(requested) == 0) == (values[0] == "true"), nil
+	}
+
+	compare, negated, err := conditionComparator(base)
+	if err != nil {
+		return false, fmt.Errorf("unknown condition operator %s", operator)
+	}
+	matches := func(value string) (bool, error) {
+		for _, expected := range values {
+			ok, err := compare(expected, value)
+			if err != nil {
+				return false, fmt.Errorf("condition operator %s: %s", operator, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if len(requested) == 0 {
+		return ifExists || negated || set == "ForAllValues", nil
+	}
+	for _, value := range requested {
+		ok, err := matches(value)
+		if err != nil {
+			return false, err
+		}
+		if set == "ForAllValues" && ok == negated {
+			return false, nil
+		}
+		if set != "ForAllValues" && ok {
+			return !negated, nil
+		}
+	}
+	return set == "ForAllValues" || negated, nil
+}
+
+// conditionComparator returns how an operator compares a policy value with
+// a request value, and whether the operator is the negation of that
+// comparison.
+func conditionComparator(operator string) (func(expected, value string) (bool, error), bool, error) {
+	negated := strings.Contains(operator, "Not")
+	operator = strings.Replace(operator, "Not", "", 1)
+
+	switch operator {
+	case "StringEquals", "BinaryEquals":
+		return func(expected, value string) (bool, error) { return expected == value, nil }, negated, nil
+	case "StringEqualsIgnoreCase":
+		return func(expected, value string) (bool, error) { return strings.EqualFold(expected, value), nil }, negated, nil
+	case "StringLike", "ArnLike", "ArnEquals":
+		return func(expected, value string) (bool, error) { return globMatch(expected, value), nil }, negated, nil
+	case "Bool":
+		return func(expected, value string) (bool, error) { return strings.EqualFold(expected, value), nil }, negated, nil
+	case "IpAddress":
+		return ipMatches, negated, nil
+	}
+	for _, comparison := range []string{"Equals", "LessThanEquals", "LessThan", "GreaterThanEquals", "GreaterThan"} {
+		if operator == "Numeric"+comparison {
+			return orderedComparator(comparison, parseConditionNumber), negated, nil
+		}
+		if operator == "Date"+comparison {
+			return orderedComparator(comparison, parseConditionDate), negated, nil
+		}
+	}
+	return nil, false, errors.New("unknown condition operator")
+}
+
+// orderedComparator compares numbers or dates parsed by parse.
+func orderedComparator(comparison string, parse func(string) (float64, error)) func(expected, value string) (bool, error) {
+	return func(expected, value string) (bool, error) {
+		want, err := parse(expected)
+		if err != nil {
+			return false, fmt.Errorf("invalid value '%s'", expected)
+		}
+		got, err := parse(value)
+		if err != nil {
+			return false, nil
+		}
+		switch comparison {
+		case "LessThan":
+			return got < want, nil
+		case "LessThanEquals":
+			return got <= want, nil
+		case "GreaterThan":
+			return got > want, nil
+		case "GreaterThanEquals":
+			return got >= want, nil
+		default:
+			return got == want, nil
+		}
+	}
+}
+
+func parseConditionNumber(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// parseConditionDate parses an ISO 8601 date or a Unix time in seconds.
+func parseConditionDate(value string) (float64, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return seconds, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return float64(t.Unix()), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid date '%s'", value)
+}
+
+// ipMatches reports whether an address lies in a CIDR block or equals a
+// single address.
+func ipMatches(expected, value string) (bool, error) {
+	ip := net.ParseIP(value)
+	if !strings.Contains(expected, "/") {
+		want := net.ParseIP(expected)
+		if want == nil {
+			return false, fmt.Errorf("invalid address '%s'", expected)
+		}
+		return ip != nil && want.Equal(ip), nil
+	}
+	_, network, err := net.ParseCIDR(expected)
+	if err != nil {
+		return false, fmt.Errorf("invalid CIDR block '%s'", expected)
+	}
+	return ip != nil && network.Contains(ip), nil
+}
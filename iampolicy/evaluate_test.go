@@ -0,0 +1,148 @@
+package iampolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	policy := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "s3:Get*", "Resource": "arn:aws:s3:::data/*"},
+		map[string]interface{}{"Sid": "Write", "Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::data/*",
+			"Condition": map[string]interface{}{
+				"Bool":              map[string]interface{}{"aws:SecureTransport": "true"},
+				"IpAddressIfExists": map[string]interface{}{"aws:SourceIp": []interface{}{"10.0.0.0/8", "192.168.1.1"}},
+			}},
+		map[string]interface{}{"Sid": "NoSecrets", "Effect": "Deny", "NotAction": "s3:List*", "Resource": "arn:aws:s3:::data/secret/*"},
+		map[string]interface{}{"Sid": "Tagged", "Effect": "Allow", "Action": "s3:DeleteObject", "Resource": "*",
+			"Condition": map[string]interface{}{
+				"ForAllValues:StringEquals": map[string]interface{}{"aws:TagKeys": []interface{}{"team", "env"}},
+				"NumericLessThanEquals":     map[string]interface{}{"s3:max-keys": "10"},
+				"StringNotEqualsIgnoreCase": map[string]interface{}{"aws:PrincipalTag/role": "intern"},
+			}},
+	}}, InlinePolicy)
+
+	testCases := []struct {
+		name      string
+		request   Request
+		expected  Decision
+		allowedBy []string
+		deniedBy  []string
+	}{
+		{
+			name:      "AllowedByWildcard",
+			request:   Request{Action: "S3:GetObject", Resource: "arn:aws:s3:::data/report.csv"},
+			expected:  Allowed,
+			allowedBy: []string{"Read"},
+		},
+		{
+			name:     "OtherResource",
+			request:  Request{Action: "s3:GetObject", Resource: "arn:aws:s3:::other/report.csv"},
+			expected: ImplicitDeny,
+		},
+		{
+			name:      "DenyOverridesAllow",
+			request:   Request{Action: "s3:GetObject", Resource: "arn:aws:s3:::data/secret/key"},
+			expected:  ExplicitDeny,
+			allowedBy: []string{"Read"},
+			deniedBy:  []string{"NoSecrets"},
+		},
+		{
+			name:      "ConditionHolds",
+			request:   Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::data/a", Context: map[string]interface{}{"aws:securetransport": true, "aws:SourceIp": "10.1.2.3"}},
+			expected:  Allowed,
+			allowedBy: []string{"Write"},
+		},
+		{
+			name:      "IfExistsWithoutKey",
+			request:   Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::data/a", Context: map[string]interface{}{"aws:SecureTransport": "true"}},
+			expected:  Allowed,
+			allowedBy: []string{"Write"},
+		},
+		{
+			name:     "ConditionFails",
+			request:  Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::data/a", Context: map[string]interface{}{"aws:SecureTransport": "true", "aws:SourceIp": "172.16.0.1"}},
+			expected: ImplicitDeny,
+		},
+		{
+			name:     "ConditionKeyMissing",
+			request:  Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::data/a"},
+			expected: ImplicitDeny,
+		},
+		{
+			name:      "ForAllValues",
+			request:   Request{Action: "s3:DeleteObject", Resource: "arn:aws:s3:::x/y", Context: map[string]interface{}{"aws:TagKeys": []interface{}{"team"}, "s3:max-keys": 5.0}},
+			expected:  Allowed,
+			allowedBy: []string{"Tagged"},
+		},
+		{
+			name:     "ForAllValuesExtraValue",
+			request:  Request{Action: "s3:DeleteObject", Resource: "arn:aws:s3:::x/y", Context: map[string]interface{}{"aws:TagKeys": []interface{}{"team", "owner"}, "s3:max-keys": 5.0}},
+			expected: ImplicitDeny,
+		},
+		{
+			name:     "NumericFails",
+			request:  Request{Action: "s3:DeleteObject", Resource: "arn:aws:s3:::x/y", Context: map[string]interface{}{"s3:max-keys": "11"}},
+			expected: ImplicitDeny,
+		},
+		{
+			name:     "NegatedMatches",
+			request:  Request{Action: "s3:DeleteObject", Resource: "arn:aws:s3:::x/y", Context: map[string]interface{}{"s3:max-keys": "1", "aws:PrincipalTag/role": "Intern"}},
+			expected: ImplicitDeny,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Evaluate(policy, tc.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Decision != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, result.Decision)
+			}
+			if !reflect.DeepEqual(result.AllowedBy, tc.allowedBy) || !reflect.DeepEqual(result.DeniedBy, tc.deniedBy) {
+				t.Errorf("Expected allowed by %v and denied by %v, but got %v and %v", tc.allowedBy, tc.deniedBy, result.AllowedBy, result.DeniedBy)
+			}
+		})
+	}
+}
+
+func TestEvaluateTrustPolicy(t *testing.T) {
+	policy := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"Service": "ec2.amazonaws.com", "AWS": "123456789012"}},
+	}}, TrustPolicy)
+
+	testCases := []struct {
+		principal string
+		expected  Decision
+	}{
+		{"Service:ec2.amazonaws.com", Allowed},
+		{"Service:lambda.amazonaws.com", ImplicitDeny},
+		{"AWS:arn:aws:iam::123456789012:role/admin", Allowed},
+		{"AWS:arn:aws:iam::210987654321:role/admin", ImplicitDeny},
+	}
+	for _, tc := range testCases {
+		result, err := Evaluate(policy, Request{Action: "sts:AssumeRole", Principal: tc.principal})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Decision != tc.expected {
+			t.Errorf("%s: expected %s, but got %s", tc.principal, tc.expected, result.Decision)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	policy := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Condition": map[string]interface{}{"StringSortOf": map[string]interface{}{"aws:username": "a"}}},
+	}}, InlinePolicy)
+
+	if _, err := Evaluate(policy, Request{Resource: "*"}); err == nil || err.Error() != "request has no action" {
+		t.Errorf("Expected missing action error, but got %v", err)
+	}
+	_, err := Evaluate(policy, Request{Action: "s3:GetObject", Resource: "arn:aws:s3:::b/k"})
+	if err == nil || err.Error() != "statement #1: unknown condition operator StringSortOf" {
+		t.Errorf("Expected unknown operator error, but got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package iampolicy_test
+
+import (
+	"errors"
+	"fmt"
+
+	"test3/iampolicy"
+)
+
+func ExampleVerify() {
+	result, err := iampolicy.Verify([]byte(`{
+		"PolicyName": "root",
+		"PolicyDocument": {
+			"Version": "2012-10-17",
+			"Statement": [{"Sid": "List", "Effect": "Allow", "Action": "iam:ListRoles", "Resource": "*"}]
+		}
+	}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(result.Passed, result.Kind)
+	for _, finding := range result.Findings {
+		fmt.Println(finding)
+	}
+	// Output:
+	// false inline
+	// [error] wildcard-resource: statement List: Resource field contains a single asterisk
+}
+
+func ExampleParse() {
+	policy, err := iampolicy.Parse([]byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(policy.Kind, len(policy.Statements), policy.Statements[0].Action)
+	// Output: trust 1 [sts:AssumeRole]
+}
+
+func ExampleValidate() {
+	err := iampolicy.Validate([]byte(`{"Version": "2012-10-17", "Statement": [{"Action": "s3:GetObject", "Resource": "*"}]}`))
+	var invalid *iampolicy.ValidationError
+	fmt.Println(errors.As(err, &invalid), err)
+	// Output: true Effect field is missing
+}
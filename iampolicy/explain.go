@@ -0,0 +1,40 @@
+package iampolicy
+
+import "errors"
+
+// RuleExplanation describes a rule as a verifier runs it.
+type RuleExplanation struct {
+	ID          string   `json:"id"`
+	Severity    Severity `json:"severity"`
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description"`
+}
+
+// ExplainRules describes the registered rules with the given IDs, or all of
+// them, with the severity and enabled state the verifier runs them with.
+func (v *Verifier) ExplainRules(ids []string) ([]RuleExplanation, error) {
+	if len(ids) == 0 {
+		ids = ruleIDs(registeredRules(v.declared...))
+	}
+	var explanations []RuleExplanation
+	for _, id := range ids {
+		rule, ok := lookupRule(v.declared, id)
+		if !ok {
+			return nil, errors.New("unknown rule " + id)
+		}
+		enabled := false
+		for _, configured := range v.rules {
+			if configured.ID() == id {
+				rule, enabled = configured, true
+				break
+			}
+		}
+		explanations = append(explanations, RuleExplanation{
+			ID:          rule.ID(),
+			Severity:    rule.Severity(),
+			Enabled:     enabled,
+			Description: rule.Description(),
+		})
+	}
+	return explanations, nil
+}
@@ -0,0 +1,56 @@
+package iampolicy
+
+import (
+	"testing"
+)
+
+func TestExplainRules(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, `
+rules:
+  wildcard-resource:
+    severity: warning
+  sid-format:
+    enabled: false
+  sid-required:
+    enabled: true
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := cfg.Verifier("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explanations, err := v.ExplainRules([]string{"wildcard-resource", "sid-format", "sid-required"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RuleExplanation{
+		{ID: "wildcard-resource", Severity: SeverityWarning, Enabled: true},
+		{ID: "sid-format", Severity: SeverityError, Enabled: false},
+		{ID: "sid-required", Severity: SeverityWarning, Enabled: true},
+	}
+	if len(explanations) != len(expected) {
+		t.Fatalf("Expected %d explanations, but got %v", len(expected), explanations)
+	}
+	for i, explanation := range explanations {
+		if explanation.ID != expected[i].ID || explanation.Severity != expected[i].Severity || explanation.Enabled != expected[i].Enabled {
+			t.Errorf("Expected %+v, but got %+v", expected[i], explanation)
+		}
+		if explanation.Description == "" {
+			t.Errorf("Expected a description for %s", explanation.ID)
+		}
+	}
+
+	all, err := newVerifier().ExplainRules(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(registeredRules()) {
+		t.Errorf("Expected every registered rule to be explained, but got %d", len(all))
+	}
+	if _, err := v.ExplainRules([]string{"no-such-rule"}); err == nil || err.Error() != "unknown rule no-such-rule" {
+		t.Errorf("Expected unknown rule error, but got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// statementKeyOrder is the canonical order of statement fields. Unknown
+// fields follow in alphabetical order.
+var statementKeyOrder = []string{"Sid", "Effect", "Principal", "NotPrincipal", "Action", "NotAction", "Resource", "NotResource", "Condition"}
+
+type orderedField struct {
+	Key   string
+	Value interface{}
+}
+
+// orderedObject is a JSON object whose fields are written in order.
+type orderedObject []orderedField
+
+// Format rewrites a policy file, either the {PolicyName, PolicyDocument}
+// wrapper or a bare document, in canonical form. With minify the policy is
+// written without whitespace and single values are not wrapped in lists.
+func Format(fileData []byte, minify bool) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(fileData))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	var canonical orderedObject
+	if _, ok := data["Statement"]; ok {
+		canonical = canonicalDocument(data, minify)
+	} else if document, ok := data["PolicyDocument"].(map[string]interface{}); ok {
+		canonical = orderedObject{}
+		if name, ok := data["PolicyName"]; ok {
+			canonical = append(canonical, orderedField{"PolicyName", name})
+		}
+		canonical = append(canonical, orderedField{"PolicyDocument", canonicalDocument(document, minify)})
+		canonical = append(canonical, remainingFields(data, "PolicyName", "PolicyDocument")...)
+	} else {
+		return nil, errors.New("not a policy document")
+	}
+
+	var buf bytes.Buffer
+	writeJSON(&buf, canonical, minify, 0)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func canonicalDocument(document map[string]interface{}, minify bool) orderedObject {
+	canonical := orderedObject{}
+	for _, key := range []string{"Version", "Id"} {
+		if value, ok := document[key]; ok {
+			canonical = append(canonical, orderedField{key, value})
+		}
+	}
+
+	if statements, ok := document["Statement"].([]interface{}); ok {
+		list := make([]interface{}, 0, len(statements))
+		for _, statement := range statements {
+			if statementMap, ok := statement.(map[string]interface{}); ok {
+				list = append(list, canonicalStatement(statementMap, minify))
+			} else {
+				list = append(list, statement)
+			}
+		}
+		canonical = append(canonical, orderedField{"Statement", list})
+	} else if statement, ok := document["Statement"].(map[string]interface{}); ok {
+		canonical = append(canonical, orderedField{"Statement", canonicalStatement(statement, minify)})
+	} else if value, ok := document["Statement"]; ok {
+		canonical = append(canonical, orderedField{"Statement", value})
+	}
+
+	return append(canonical, remainingFields(document, "Version", "Id", "Statement")...)
+}
+
+func canonicalStatement(statement map[string]interface{}, minify bool) orderedObject {
+	canonical := orderedObject{}
+	for _, key := range statementKeyOrder {
+		value, ok := statement[key]
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Action", "NotAction", "Resource", "NotResource":
+			value = canonicalList(value, minify)
+		case "Principal", "NotPrincipal":
+			if principal, ok := value.(map[string]interface{}); ok {
+				value = canonicalMap(principal, func(v interface{}) interface{} { return canonicalList(v, minify) })
+			}
+		case "Condition":
+			if condition, ok := value.(map[string]interface{}); ok {
+				value = canonicalMap(condition, func(operator interface{}) interface{} {
+					keys, ok := operator.(map[string]interface{})
+					if !ok {
+						return operator
+					}
+					return canonicalMap(keys, sortedStrings)
+				})
+			}
+		}
+		canonical = append(canonical, orderedField{key, value})
+	}
+	return append(canonical, remainingFields(statement, statementKeyOrder...)...)
+}
+
+// canonicalList sorts and deduplicates a string-or-list field. Single
+// values are written as lists unless minifying.
+func canonicalList(value interface{}, minify bool) interface{} {
+	switch v := value.(type) {
+	case string:
+		if minify {
+			return v
+		}
+		return []interface{}{v}
+	case []interface{}:
+		for _, item := range v {
+			if _, ok := item.(string); !ok {
+				return value
+			}
+		}
+		unique := sortedUnique(stringList(v))
+		if minify && len(unique) == 1 {
+			return unique[0]
+		}
+		list := make([]interface{}, len(unique))
+		for i, s := range unique {
+			list[i] = s
+		}
+		return list
+	}
+	return value
+}
+
+// sortedStrings sorts a list of strings, leaving any other value alone.
+func sortedStrings(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	for _, item := range list {
+		if _, ok := item.(string); !ok {
+			return value
+		}
+	}
+	sorted := append([]interface{}{}, list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].(string) < sorted[j].(string)
+	})
+	return sorted
+}
+
+func canonicalMap(m map[string]interface{}, canonicalValue func(interface{}) interface{}) orderedObject {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	canonical := make(orderedObject, 0, len(keys))
+	for _, key := range keys {
+		canonical = append(canonical, orderedField{key, canonicalValue(m[key])})
+	}
+	return canonical
+}
+
+// remainingFields returns the fields not in known, sorted by key.
+func remainingFields(m map[string]interface{}, known ...string) orderedObject {
+	rest := map[string]interface{}{}
+	for key, value := range m {
+		rest[key] = value
+	}
+	for _, key := range known {
+		delete(rest, key)
+	}
+	return canonicalMap(rest, func(v interface{}) interface{} { return v })
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}, minify bool, level int) {
+	newline := func(level int) {
+		if !minify {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat("    ", level))
+		}
+	}
+
+	switch v := value.(type) {
+	case orderedObject:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteByte('{')
+		for i, field := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(level + 1)
+			writeJSON(buf, field.Key, minify, level+1)
+			buf.WriteByte(':')
+			if !minify {
+				buf.WriteByte(' ')
+			}
+			writeJSON(buf, field.Value, minify, level+1)
+		}
+		newline(level)
+		buf.WriteByte('}')
+	case map[string]interface{}:
+		writeJSON(buf, canonicalMap(v, func(v interface{}) interface{} { return v }), minify, level)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(level + 1)
+			writeJSON(buf, item, minify, level+1)
+		}
+		newline(level)
+		buf.WriteByte(']')
+	default:
+		buf.Write(minifiedJSON(v))
+	}
+}
@@ -0,0 +1,93 @@
+package iampolicy
+
+import (
+	"testing"
+)
+
+const unformattedPolicyJSON = `{"Statement": [{"Condition": {"StringLike": {"s3:prefix": ["logs/", "home/"]}, "Bool": {"aws:SecureTransport": "true"}},
+  "Resource": ["arn:aws:s3:::b", "arn:aws:s3:::a", "arn:aws:s3:::b"], "Action": "s3:ListBucket", "Effect": "Allow", "Sid": "List"},
+  {"Principal": {"Service": "ec2.amazonaws.com", "AWS": ["arn:aws:iam::2:root", "arn:aws:iam::1:root"]}, "Effect": "Allow", "Action": "sts:AssumeRole"}],
+  "Version": "2012-10-17"}`
+
+const formattedPolicyJSON = `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Sid": "List",
+            "Effect": "Allow",
+            "Action": [
+                "s3:ListBucket"
+            ],
+            "Resource": [
+                "arn:aws:s3:::a",
+                "arn:aws:s3:::b"
+            ],
+            "Condition": {
+                "Bool": {
+                    "aws:SecureTransport": "true"
+                },
+                "StringLike": {
+                    "s3:prefix": [
+                        "home/",
+                        "logs/"
+                    ]
+                }
+            }
+        },
+        {
+            "Effect": "Allow",
+            "Principal": {
+                "AWS": [
+                    "arn:aws:iam::1:root",
+                    "arn:aws:iam::2:root"
+                ],
+                "Service": [
+                    "ec2.amazonaws.com"
+                ]
+            },
+            "Action": [
+                "sts:AssumeRole"
+            ]
+        }
+    ]
+}
+`
+
+const minifiedPolicyJSON = `{"Version":"2012-10-17","Statement":[{"Sid":"List","Effect":"Allow","Action":"s3:ListBucket","Resource":["arn:aws:s3:::a","arn:aws:s3:::b"],"Condition":{"Bool":{"aws:SecureTransport":"true"},"StringLike":{"s3:prefix":["home/","logs/"]}}},{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::1:root","arn:aws:iam::2:root"],"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}
+`
+
+func TestFormatPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		minify   bool
+		expected string
+	}{
+		{"Canonical", unformattedPolicyJSON, false, formattedPolicyJSON},
+		{"Idempotent", formattedPolicyJSON, false, formattedPolicyJSON},
+		{"Minify", unformattedPolicyJSON, true, minifiedPolicyJSON},
+		{"MinifyIdempotent", minifiedPolicyJSON, true, minifiedPolicyJSON},
+		{
+			"Wrapper",
+			`{"PolicyDocument": {"Statement": [{"Resource": "*", "Action": ["b", "a"], "Effect": "Deny", "Condition": {"NumericLessThan": {"aws:MultiFactorAuthAge": 3600}}}], "Version": "2012-10-17"}, "PolicyName": "root"}`,
+			true,
+			`{"PolicyName":"root","PolicyDocument":{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["a","b"],"Resource":"*","Condition":{"NumericLessThan":{"aws:MultiFactorAuthAge":3600}}}]}}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Format([]byte(tc.input), tc.minify)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res) != tc.expected {
+				t.Errorf("Expected:\n%s\nbut got:\n%s", tc.expected, res)
+			}
+		})
+	}
+
+	if _, err := Format([]byte(`{"format_version": "1.2"}`), false); err == nil {
+		t.Errorf("Expected non-nil error for input that is not a policy, got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package iampolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// generatePolicy returns a policy document allowing exactly the actions and
+// resources in the access log. Actions of a service used on the same
+// resources share a statement.
+func generatePolicy(access accessLog) map[string]interface{} {
+	type group struct {
+		service   string
+		actions   []string
+		resources []string
+	}
+	var groups []*group
+	byKey := map[string]*group{}
+	for _, action := range access.actions() {
+		service, _, _ := strings.Cut(action, ":")
+		resources := access.resources(action)
+		key := service + "\x00" + strings.Join(resources, "\x00")
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{service: service, resources: resources}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.actions = append(g.actions, action)
+	}
+
+	statements := make([]interface{}, 0, len(groups))
+	count := map[string]int{}
+	for _, g := range groups {
+		count[g.service]++
+		sid := sidName(g.service) + "Access"
+		if count[g.service] > 1 {
+			sid += fmt.Sprint(count[g.service])
+		}
+		statements = append(statements, map[string]interface{}{
+			"Sid":      sid,
+			"Effect":   "Allow",
+			"Action":   interfaceList(g.actions),
+			"Resource": interfaceList(g.resources),
+		})
+	}
+	return map[string]interface{}{"Version": "2012-10-17", "Statement": statements}
+}
+
+// sidName turns a service prefix such as "ec2-instance-connect" into an
+// alphanumeric Sid prefix such as "Ec2InstanceConnect".
+func sidName(service string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range service {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// GenerateFromCloudTrail reads CloudTrail logs, .json or .json.gz files or
+// directories of them, and returns a least-privilege inline policy allowing
+// the actions and resources the role, given by name or ARN, used. Actions
+// the catalog lists without resource-level permissions are allowed on "*".
+// The policy is checked with the default rules under the given PolicyName.
+func GenerateFromCloudTrail(paths []string, role, name string, catalog *Catalog) (*Policy, error) {
+	records, err := readCloudTrail(paths)
+	if err != nil {
+		return nil, err
+	}
+	access := collectAccess(records, role)
+	if len(access) == 0 {
+		return nil, fmt.Errorf("no events found for role %s", role)
+	}
+	resourceless := access.allowAnyResource(catalog)
+
+	generated := generatePolicy(access)
+	v := newVerifier()
+	for i, rule := range v.rules {
+		if r, ok := rule.(wildcardResourceRule); ok {
+			r.allowedActions = resourceless
+			v.rules[i] = r
+		}
+	}
+	findings, err := v.verifyIAMRolePolicy(map[string]interface{}{"PolicyName": name, "PolicyDocument": generated})
+	if err != nil {
+		return nil, fmt.Errorf("generated policy is not valid: %s", err)
+	}
+	if !passed(findings) {
+		messages := make([]string, len(findings))
+		for i, finding := range findings {
+			messages[i] = finding.String()
+		}
+		return nil, fmt.Errorf("generated policy does not pass verification: %s", strings.Join(messages, "; "))
+	}
+	return parsePolicy(generated, InlinePolicy), nil
+}
@@ -0,0 +1,158 @@
+package iampolicy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const cloudTrailJSON = `{"Records": [
+  {"eventTime": "2024-05-01T10:00:00Z", "eventSource": "s3.amazonaws.com", "eventName": "GetObject", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"type": "AssumedRole", "arn": "arn:aws:sts::123456789012:assumed-role/app/i-1", "sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:s3:::data/report.csv"}, {"ARN": "arn:aws:s3:::data"}]},
+  {"eventTime": "2024-05-01T10:01:00Z", "eventSource": "s3.amazonaws.com", "eventName": "PutObject", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:s3:::data/report.csv"}, {"ARN": "arn:aws:s3:::data"}]},
+  {"eventTime": "2024-05-01T10:02:00Z", "eventSource": "s3.amazonaws.com", "eventName": "DeleteObject", "awsRegion": "us-east-1", "recipientAccountId": "123456789012", "errorCode": "AccessDenied",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:s3:::data/report.csv"}]},
+  {"eventTime": "2024-05-01T10:03:00Z", "eventSource": "s3.amazonaws.com", "eventName": "ListBuckets", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/other", "userName": "other"}}}}
+]}`
+
+const cloudTrailGzipJSON = `{"Records": [
+  {"eventTime": "2024-05-02T08:00:00Z", "eventSource": "lambda.amazonaws.com", "eventName": "GetFunction20150331v2", "awsRegion": "eu-west-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}}},
+  {"eventTime": "2024-05-02T08:01:00Z", "eventSource": "sqs.amazonaws.com", "eventName": "SendMessage", "awsRegion": "eu-west-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:sqs:eu-west-1:123456789012:jobs"}]}
+]}`
+
+// writeCloudTrail writes a plain and a gzip-compressed log file in a
+// nested directory and returns the top directory.
+func writeCloudTrail(t *testing.T) string {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "2024", "05")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "a.json"), []byte(cloudTrailJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(cloudTrailGzipJSON)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "b.json.gz"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "README.txt"), []byte("not a log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerateFromCloudTrail(t *testing.T) {
+	dir := writeCloudTrail(t)
+	catalog, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, role := range []string{"app", "arn:aws:iam::123456789012:role/app"} {
+		generated, err := GenerateFromCloudTrail([]string{dir}, role, "generated", catalog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		document := generated.Document
+		expected := []interface{}{
+			map[string]interface{}{"Sid": "LambdaAccess", "Effect": "Allow", "Action": []interface{}{"lambda:GetFunction"}, "Resource": []interface{}{"arn:aws:lambda:eu-west-1:123456789012:*"}},
+			map[string]interface{}{"Sid": "S3Access", "Effect": "Allow", "Action": []interface{}{"s3:GetObject", "s3:PutObject"}, "Resource": []interface{}{"arn:aws:s3:::data", "arn:aws:s3:::data/report.csv"}},
+			map[string]interface{}{"Sid": "SqsAccess", "Effect": "Allow", "Action": []interface{}{"sqs:SendMessage"}, "Resource": []interface{}{"arn:aws:sqs:eu-west-1:123456789012:jobs"}},
+		}
+		if !reflect.DeepEqual(document["Statement"], expected) {
+			t.Errorf("Role %s: expected statements\n%v\nbut got\n%v", role, expected, document["Statement"])
+		}
+	}
+
+	if _, err := GenerateFromCloudTrail([]string{dir}, "missing", "generated", catalog); err == nil {
+		t.Errorf("Expected non-nil error for role without events, got nil")
+	}
+
+	wildcard := filepath.Join(t.TempDir(), "wildcard.json")
+	if err := os.WriteFile(wildcard, []byte(`{"Records": [{"eventSource": "ec2.amazonaws.com", "eventName": "DescribeInstances", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+		"userIdentity": {"sessionContext": {"sessionIssuer": {"userName": "app"}}}, "resources": [{"ARN": "*"}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expected := "generated policy does not pass verification: [error] wildcard-resource: statement Ec2Access: Resource field contains a single asterisk"
+	if _, err := GenerateFromCloudTrail([]string{wildcard}, "app", "generated", catalog); err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, but got %v", expected, err)
+	}
+}
+
+func TestGenerateFromCloudTrailResourcelessActions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resourceless.json")
+	if err := os.WriteFile(path, []byte(`{"Records": [
+  {"eventSource": "sts.amazonaws.com", "eventName": "GetCallerIdentity", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"userName": "app"}}}},
+  {"eventSource": "s3.amazonaws.com", "eventName": "ListBuckets", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"userName": "app"}}}},
+  {"eventSource": "sqs.amazonaws.com", "eventName": "SendMessage", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"userName": "app"}}}}
+]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := LoadCatalog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := GenerateFromCloudTrail([]string{path}, "app", "generated", catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"Sid": "S3Access", "Effect": "Allow", "Action": []interface{}{"s3:ListAllMyBuckets"}, "Resource": []interface{}{"*"}},
+		map[string]interface{}{"Sid": "SqsAccess", "Effect": "Allow", "Action": []interface{}{"sqs:SendMessage"}, "Resource": []interface{}{"arn:aws:sqs:us-east-1:123456789012:*"}},
+		map[string]interface{}{"Sid": "StsAccess", "Effect": "Allow", "Action": []interface{}{"sts:GetCallerIdentity"}, "Resource": []interface{}{"*"}},
+	}
+	if !reflect.DeepEqual(generated.Document["Statement"], expected) {
+		t.Errorf("Expected statements\n%v\nbut got\n%v", expected, generated.Document["Statement"])
+	}
+}
+
+func TestCloudTrailResources(t *testing.T) {
+	testCases := []struct {
+		source   string
+		region   string
+		expected string
+	}{
+		{"lambda.amazonaws.com", "eu-west-1", "arn:aws:lambda:eu-west-1:123456789012:*"},
+		{"s3.amazonaws.com", "eu-west-1", "arn:aws:s3:::*"},
+		{"iam.amazonaws.com", "us-east-1", "arn:aws:iam::123456789012:*"},
+		{"sts.amazonaws.com", "eu-west-1", "arn:aws:sts::123456789012:*"},
+		{"route53.amazonaws.com", "us-east-1", "arn:aws:route53:::*"},
+		{"sqs.amazonaws.com", "cn-north-1", "arn:aws-cn:sqs:cn-north-1:123456789012:*"},
+	}
+	for _, tc := range testCases {
+		record := cloudTrailRecord{EventSource: tc.source, AWSRegion: tc.region, RecipientAccountID: "123456789012"}
+		if res := record.resources(); !reflect.DeepEqual(res, []string{tc.expected}) {
+			t.Errorf("%s: expected %s, but got %v", tc.source, tc.expected, res)
+		}
+	}
+}
+
+func TestSidName(t *testing.T) {
+	for service, expected := range map[string]string{"s3": "S3", "ec2-instance-connect": "Ec2InstanceConnect", "dynamodb": "Dynamodb"} {
+		if res := sidName(service); res != expected {
+			t.Errorf("sidName(%q): expected %q, but got %q", service, expected, res)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package iampolicy
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// globMatch reports whether value matches an IAM-style pattern, where "*"
+// matches any sequence of characters and "?" matches a single character.
+func globMatch(pattern, value string) bool {
+	p, v := 0, 0
+	starP, starV := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			starP, starV = p, v
+			p++
+		case starP >= 0:
+			p = starP + 1
+			starV++
+			v = starV
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// actionMatch matches IAM actions, which are case-insensitive.
+func actionMatch(pattern, action string) bool {
+	return globMatch(strings.ToLower(pattern), strings.ToLower(action))
+}
+
+// globSubsumes reports whether every value matched by pattern specific is
+// also matched by pattern general, e.g. "s3:Get*" subsumes "s3:GetObject*".
+// A "*" in general may stand for any part of specific, wildcards included,
+// while "?" and literals only stand for single non-"*" characters.
+func globSubsumes(general, specific string) bool {
+	memo := map[[2]int]bool{}
+	var subsumes func(i, j int) bool
+	subsumes = func(i, j int) bool {
+		key := [2]int{i, j}
+		if result, ok := memo[key]; ok {
+			return result
+		}
+		var result bool
+		switch {
+		case i == len(general):
+			result = j == len(specific)
+		case general[i] == '*':
+			result = subsumes(i+1, j) || (j < len(specific) && subsumes(i, j+1))
+		case j == len(specific) || specific[j] == '*':
+			result = false
+		case general[i] == '?':
+			result = subsumes(i+1, j+1)
+		default:
+			result = general[i] == specific[j] && subsumes(i+1, j+1)
+		}
+		memo[key] = result
+		return result
+	}
+	return subsumes(0, 0)
+}
+
+// actionSubsumes is globSubsumes for case-insensitive IAM actions.
+func actionSubsumes(general, specific string) bool {
+	return globSubsumes(strings.ToLower(general), strings.ToLower(specific))
+}
+
+// pathMatch matches a slash-separated file path against a pattern in which
+// "**" stands for any number of directories and other segments follow
+// filepath.Match.
+func pathMatch(pattern, path string) bool {
+	return matchSegments(strings.Split(filepath.ToSlash(pattern), "/"), strings.Split(filepath.ToSlash(path), "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,89 @@
+package iampolicy
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"s3:Get*", "s3:GetObject", true},
+		{"s3:Get*", "s3:PutObject", false},
+		{"s3:*Object", "s3:GetObject", true},
+		{"s3:*Object", "s3:GetObjectAcl", false},
+		{"arn:aws:s3:::bucket/*/logs", "arn:aws:s3:::bucket/a/b/logs", true},
+		{"iam:?etRole", "iam:GetRole", true},
+		{"iam:?etRole", "iam:etRole", false},
+		{"a*b*c", "abxbc", true},
+		{"a*b*c", "acb", false},
+		{"", "", true},
+		{"", "a", false},
+	}
+
+	for _, tc := range testCases {
+		if res := globMatch(tc.pattern, tc.value); res != tc.expected {
+			t.Errorf("globMatch(%q, %q): expected %t, but got %t", tc.pattern, tc.value, tc.expected, res)
+		}
+	}
+
+	if !actionMatch("S3:get*", "s3:GetObject") {
+		t.Errorf("Expected actionMatch to ignore case")
+	}
+}
+
+func TestGlobSubsumes(t *testing.T) {
+	testCases := []struct {
+		general  string
+		specific string
+		expected bool
+	}{
+		{"*", "anything*", true},
+		{"s3:Get*", "s3:GetObject", true},
+		{"s3:Get*", "s3:GetObject*", true},
+		{"s3:GetObject*", "s3:Get*", false},
+		{"s3:GetObject", "s3:GetObject", true},
+		{"s3:*Object", "s3:Get*Object", true},
+		{"s3:*Object", "s3:Get*", false},
+		{"iam:?etRole", "iam:GetRole", true},
+		{"iam:?etRole", "iam:?etRole", true},
+		{"iam:GetRole", "iam:?etRole", false},
+		{"iam:?etRole", "iam:*etRole", false},
+		{"a*b*c", "a*xb*yc", true},
+	}
+
+	for _, tc := range testCases {
+		if res := globSubsumes(tc.general, tc.specific); res != tc.expected {
+			t.Errorf("globSubsumes(%q, %q): expected %t, but got %t", tc.general, tc.specific, tc.expected, res)
+		}
+	}
+
+	if !actionSubsumes("S3:get*", "s3:GetObject") {
+		t.Errorf("Expected actionSubsumes to ignore case")
+	}
+}
+
+func TestPathMatch(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"policy.json", "policy.json", true},
+		{"*.json", "policy.json", true},
+		{"*.json", "roles/policy.json", false},
+		{"roles/*.json", "roles/policy.json", true},
+		{"**/*.json", "policy.json", true},
+		{"**/*.json", "a/b/policy.json", true},
+		{"legacy/**", "legacy/a/b/policy.json", true},
+		{"legacy/**", "current/policy.json", false},
+	}
+
+	for _, tc := range testCases {
+		if res := pathMatch(tc.pattern, tc.path); res != tc.expected {
+			t.Errorf("pathMatch(%q, %q): expected %t, but got %t", tc.pattern, tc.path, tc.expected, res)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parseInput decodes the content of an input file. Besides plain JSON it
+// accepts a URL-encoded policy document, as returned by the IAM API, either
+// raw or wrapped in a JSON string.
+func parseInput(fileData []byte) (interface{}, error) {
+	var data interface{}
+	err := json.Unmarshal(fileData, &data)
+	if err == nil {
+		if encoded, ok := data.(string); ok {
+			return decodePolicyDocument(encoded)
+		}
+		return data, nil
+	}
+
+	trimmed := bytes.TrimSpace(fileData)
+	if !bytes.HasPrefix(trimmed, []byte("%")) {
+		return nil, err
+	}
+	return decodePolicyDocument(string(trimmed))
+}
+
+// decodePolicyDocument returns a policy document given either as a
+// dictionary, a JSON string or a URL-encoded JSON string.
+func decodePolicyDocument(value interface{}) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		text := strings.TrimSpace(v)
+		if strings.HasPrefix(text, "%") {
+			decoded, err := url.PathUnescape(text)
+			if err != nil {
+				return nil, fmt.Errorf("policy is not a valid URL-encoded string: %s", err)
+			}
+			text = decoded
+		}
+
+		var document map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &document); err != nil {
+			return nil, fmt.Errorf("policy is not valid JSON: %s", err)
+		}
+		return document, nil
+	default:
+		return nil, errors.New("PolicyDocument is not a dictionary")
+	}
+}
+
+// normalizePolicyInput converts the supported input shapes into the
+// {PolicyName, PolicyDocument} form and tells which kind of policy it holds:
+//   - the {PolicyName, PolicyDocument} wrapper itself,
+//   - a bare policy document copied from the console,
+//   - an `aws iam get-role-policy` response,
+//   - an `aws iam get-policy-version` response.
+func normalizePolicyInput(input interface{}) (map[string]interface{}, PolicyKind, error) {
+	data, ok := input.(map[string]interface{})
+	if !ok {
+		return nil, InlinePolicy, errors.New("input is not a dictionary")
+	}
+
+	if version, ok := data["PolicyVersion"].(map[string]interface{}); ok {
+		document, err := decodePolicyDocument(version["Document"])
+		if err != nil {
+			return nil, ManagedPolicy, err
+		}
+		return map[string]interface{}{"PolicyDocument": document}, ManagedPolicy, nil
+	}
+
+	if _, ok := data["Statement"]; ok {
+		kind := InlinePolicy
+		if hasPrincipal(data) {
+			kind = TrustPolicy
+		}
+		return map[string]interface{}{"PolicyDocument": data}, kind, nil
+	}
+
+	if value, ok := data["PolicyDocument"]; ok {
+		if _, ok := data["RoleName"]; ok {
+			// get-role-policy answers with RoleName next to the wrapper
+			// fields; it is not part of the policy itself.
+			data = map[string]interface{}{"PolicyName": data["PolicyName"], "PolicyDocument": value}
+		}
+		if _, ok := value.(string); ok {
+			document, err := decodePolicyDocument(value)
+			if err != nil {
+				return nil, InlinePolicy, err
+			}
+			normalized := map[string]interface{}{}
+			for key, v := range data {
+				normalized[key] = v
+			}
+			normalized["PolicyDocument"] = document
+			data = normalized
+		}
+	}
+
+	return data, InlinePolicy, nil
+}
+
+// verifyPolicyInput verifies a normalized input of the given kind.
+func (v *Verifier) verifyPolicyInput(data map[string]interface{}, kind PolicyKind) ([]Finding, error) {
+	if kind == InlinePolicy {
+		return v.verifyIAMRolePolicy(data)
+	}
+	document, _ := data["PolicyDocument"].(map[string]interface{})
+	return v.verifyPolicyDocument(document, kind)
+}
+
+// loadPolicy returns the validated, parsed policy of a decoded input holding
+// a single policy.
+func loadPolicy(input interface{}) (*Policy, error) {
+	if data, ok := input.(map[string]interface{}); ok && (isTerraformPlan(data) || isAuthorizationDetails(data)) {
+		return nil, errors.New("input holds more than one policy")
+	}
+	data, kind, err := normalizePolicyInput(input)
+	if err != nil {
+		return nil, err
+	}
+	v := newVerifier()
+	document, err := v.validateIAMRolePolicy(data)
+	if err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+	if ok, err := v.validatePolicyDocument(document, kind); !ok {
+		return nil, &ValidationError{Err: err}
+	}
+	return parsePolicy(document, kind), nil
+}
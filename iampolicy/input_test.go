@@ -0,0 +1,87 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadJSONsFromFileInputFormats(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		expectedResult bool
+		expectedError  string
+	}{
+		{
+			name:           "Wrapper",
+			content:        `{"PolicyName": "root", "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": "arn:aws:s3:::bucket/*"}]}}`,
+			expectedResult: true,
+		},
+		{
+			name:           "BarePolicyDocument",
+			content:        `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+			expectedResult: false,
+		},
+		{
+			name:           "BareTrustPolicy",
+			content:        `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": {"Service": "lambda.amazonaws.com"}}]}`,
+			expectedResult: true,
+		},
+		{
+			name:           "GetRolePolicyResponse",
+			content:        `{"RoleName": "app", "PolicyName": "read", "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}}`,
+			expectedResult: true,
+		},
+		{
+			name:           "GetRolePolicyResponseURLEncoded",
+			content:        `{"RoleName": "app", "PolicyName": "read", "PolicyDocument": "%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22s3%3AGetObject%22%2C%22Resource%22%3A%22%2A%22%7D%5D%7D"}`,
+			expectedResult: false,
+		},
+		{
+			name:           "GetPolicyVersionResponse",
+			content:        `{"PolicyVersion": {"Document": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}, "VersionId": "v2", "IsDefaultVersion": true, "CreateDate": "2024-01-01T00:00:00Z"}}`,
+			expectedResult: true,
+		},
+		{
+			name:           "URLEncodedDocument",
+			content:        "%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22s3%3AGetObject%22%2C%22Resource%22%3A%22arn%3Aaws%3As3%3A%3A%3Abucket%2F%2A%22%7D%5D%7D\n",
+			expectedResult: true,
+		},
+		{
+			name:           "URLEncodedDocumentInJSONString",
+			content:        `"%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22s3%3AGetObject%22%2C%22Resource%22%3A%22%2A%22%7D%5D%7D"`,
+			expectedResult: false,
+		},
+		{
+			name:          "URLEncodedDocumentInvalidJSON",
+			content:       "%7B%22Version%22",
+			expectedError: "policy is not valid JSON: unexpected end of JSON input",
+		},
+		{
+			name:          "PolicyDocumentNotDictionary",
+			content:       `{"PolicyName": "root", "PolicyDocument": 1}`,
+			expectedError: "PolicyDocument is not a dictionary",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := filePassed(newVerifier(), path)
+			if err != nil {
+				if tc.expectedError != err.Error() {
+					t.Errorf("Expected error '%s', but got %v", tc.expectedError, err)
+				}
+			} else if tc.expectedError != "" {
+				t.Errorf("Expected error '%s', but got nil", tc.expectedError)
+			} else if tc.expectedResult != res {
+				t.Errorf("Expected result '%t', but got %t", tc.expectedResult, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package iampolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VerifyKubernetesObject verifies the policy documents embedded in an IAM
+// custom resource of the AWS Controllers for Kubernetes (ACK) or of the
+// Crossplane AWS providers. Objects of other kinds hold no policies.
+func (v *Verifier) VerifyKubernetesObject(object map[string]interface{}) []PolicyResult {
+	apiVersion, _ := object["apiVersion"].(string)
+	group, _, _ := strings.Cut(apiVersion, "/")
+	kind, _ := object["kind"].(string)
+	spec, _ := object["spec"].(map[string]interface{})
+	forProvider, _ := spec["forProvider"].(map[string]interface{})
+
+	var results []PolicyResult
+	verify := func(source string, value interface{}, kind PolicyKind) {
+		if value == nil {
+			return
+		}
+		results = append(results, v.verifyDocument(source, value, kind))
+	}
+
+	switch group + "/" + kind {
+	case "iam.services.k8s.aws/Role":
+		verify("spec.assumeRolePolicyDocument", spec["assumeRolePolicyDocument"], TrustPolicy)
+		inlinePolicies, _ := spec["inlinePolicies"].(map[string]interface{})
+		names := make([]string, 0, len(inlinePolicies))
+		for name := range inlinePolicies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			verify(fmt.Sprintf("spec.inlinePolicies[%q]", name), inlinePolicies[name], InlinePolicy)
+		}
+	case "iam.services.k8s.aws/Policy":
+		verify("spec.policyDocument", spec["policyDocument"], ManagedPolicy)
+	case "iam.aws.upbound.io/Role":
+		verify("spec.forProvider.assumeRolePolicy", forProvider["assumeRolePolicy"], TrustPolicy)
+		inlinePolicies, _ := forProvider["inlinePolicy"].([]interface{})
+		for _, item := range inlinePolicies {
+			inline, _ := item.(map[string]interface{})
+			name, _ := inline["name"].(string)
+			verify(fmt.Sprintf("spec.forProvider.inlinePolicy[%q]", name), inline["policy"], InlinePolicy)
+		}
+	case "iam.aws.upbound.io/Policy":
+		verify("spec.forProvider.policy", forProvider["policy"], ManagedPolicy)
+	case "iam.aws.upbound.io/RolePolicy":
+		verify("spec.forProvider.policy", forProvider["policy"], InlinePolicy)
+	case "iam.aws.crossplane.io/Role":
+		verify("spec.forProvider.assumeRolePolicyDocument", forProvider["assumeRolePolicyDocument"], TrustPolicy)
+	case "iam.aws.crossplane.io/Policy":
+		verify("spec.forProvider.document", forProvider["document"], ManagedPolicy)
+	}
+	return results
+}
@@ -0,0 +1,213 @@
+package iampolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// optimizedStatement is a statement of the optimized policy, possibly the
+// result of merging several statements of the original.
+type optimizedStatement struct {
+	name     string
+	raw      map[string]interface{}
+	action   []string
+	resource []string
+}
+
+// Optimize returns a policy granting the same access as policy with
+// fewer statements and entries, and the steps justifying each change:
+// covered statements are removed, statements with the same Effect,
+// Principal, Condition and Resource are merged, action lists are collapsed
+// into wildcards the catalog shows grant nothing more, and entries covered
+// by another entry of their list are removed.
+func Optimize(policy *Policy, catalog *Catalog) (*Policy, []string) {
+	var steps []string
+	scopes := statementScopes(policy.Statements)
+
+	var statements []*optimizedStatement
+	merged := map[string]*optimizedStatement{}
+	for i, statement := range policy.Statements {
+		if other, ok := coveringStatement(policy.Statements, scopes, i); ok {
+			steps = append(steps, fmt.Sprintf("statement %s removed: covered by statement %s", statement.Name(), other.Name()))
+			continue
+		}
+		key := string(scopes[i]) + string(minifiedJSON(sortedUnique(statement.Resource)))
+		if target, ok := merged[key]; ok {
+			target.action = append(target.action, statement.Action...)
+			steps = append(steps, fmt.Sprintf("statement %s merged into statement %s: same Effect, Principal, Condition and Resource", statement.Name(), target.name))
+			continue
+		}
+		optimized := &optimizedStatement{
+			name:     statement.Name(),
+			raw:      statement.Raw,
+			action:   append([]string{}, statement.Action...),
+			resource: statement.Resource,
+		}
+		merged[key] = optimized
+		statements = append(statements, optimized)
+	}
+
+	list := make([]interface{}, 0, len(statements))
+	for _, statement := range statements {
+		actions, collapsed := catalog.collapse(statement.action)
+		for _, group := range collapsed {
+			steps = append(steps, fmt.Sprintf("statement %s: Action '%s' replaced by '%s': the catalog lists no other action matching it", statement.name, strings.Join(group.replaced, "', '"), group.pattern))
+		}
+		actions, removed := pruneCovered(actions, actionSubsumes)
+		steps = append(steps, prunedSteps(statement.name, "Action", removed, actionSubsumes)...)
+		resources, removed := pruneCovered(statement.resource, globSubsumes)
+		steps = append(steps, prunedSteps(statement.name, "Resource", removed, globSubsumes)...)
+
+		raw := map[string]interface{}{}
+		for key, value := range statement.raw {
+			raw[key] = value
+		}
+		if _, ok := raw["Action"]; ok {
+			raw["Action"] = interfaceList(actions)
+		}
+		if _, ok := raw["Resource"]; ok {
+			raw["Resource"] = interfaceList(resources)
+		}
+		list = append(list, raw)
+	}
+
+	document := map[string]interface{}{}
+	for key, value := range policy.Document {
+		document[key] = value
+	}
+	document["Statement"] = list
+	return parsePolicy(document, policy.Kind), steps
+}
+
+// collapsed records action entries replaced by a wildcard.
+type collapsed struct {
+	pattern  string
+	replaced []string
+}
+
+// collapse replaces groups of actions by the widest wildcard whose catalog
+// expansion the actions already grant. Only actions of services the catalog
+// covers are collapsed, and only where a wildcard replaces two or more
+// entries.
+func (c *Catalog) collapse(actions []string) ([]string, []collapsed) {
+	granted := map[string]bool{}
+	for _, action := range actions {
+		for _, expanded := range c.expand(action) {
+			granted[strings.ToLower(expanded)] = true
+		}
+	}
+
+	var patterns []string
+	seen := map[string]bool{}
+	for _, action := range actions {
+		if !c.contains(action) || !c.covers(action) {
+			continue
+		}
+		pattern := c.widestWildcard(action, granted)
+		if pattern != "" && !seen[strings.ToLower(pattern)] {
+			seen[strings.ToLower(pattern)] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	var result []string
+	var collapses []collapsed
+	replacedBy := map[int]int{}
+	for _, pattern := range patterns {
+		var replaced []int
+		for i, action := range actions {
+			if _, ok := replacedBy[i]; !ok && !strings.ContainsAny(action, "*?") && actionMatch(pattern, action) {
+				replaced = append(replaced, i)
+			}
+		}
+		if len(replaced) < 2 {
+			continue
+		}
+		group := collapsed{pattern: pattern}
+		for _, i := range replaced {
+			replacedBy[i] = len(collapses)
+			group.replaced = append(group.replaced, actions[i])
+		}
+		collapses = append(collapses, group)
+	}
+	added := map[int]bool{}
+	for i, action := range actions {
+		index, ok := replacedBy[i]
+		if !ok {
+			result = append(result, action)
+		} else if !added[index] {
+			added[index] = true
+			result = append(result, collapses[index].pattern)
+		}
+	}
+	return result, collapses
+}
+
+// widestWildcard returns the shortest wildcard ending at a word boundary of
+// action, e.g. "s3:Get*" or "s3:GetObject*" for "s3:GetObjectAcl", all of
+// whose catalog actions are granted, or "" when there is none.
+func (c *Catalog) widestWildcard(action string, granted map[string]bool) string {
+	colon := strings.Index(action, ":")
+	for k := colon + 1; k <= len(action); k++ {
+		if k > colon+1 && k < len(action) && !unicode.IsUpper(rune(action[k])) {
+			continue
+		}
+		pattern := action[:k] + "*"
+		safe := true
+		for _, expanded := range c.expand(pattern) {
+			if !granted[strings.ToLower(expanded)] {
+				safe = false
+				break
+			}
+		}
+		if safe {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// pruneCovered removes the entries of a list covered by another remaining
+// entry, keeping the first of equivalent entries, and returns the removed
+// entries paired with the entry covering them.
+func pruneCovered(list []string, subsumes func(general, specific string) bool) ([]string, [][2]string) {
+	kept := append([]string{}, list...)
+	var removed [][2]string
+	for i := 0; i < len(kept); {
+		covering := -1
+		for j, other := range kept {
+			if i != j && subsumes(other, kept[i]) && (j < i || !subsumes(kept[i], other)) {
+				covering = j
+				break
+			}
+		}
+		if covering < 0 {
+			i++
+			continue
+		}
+		removed = append(removed, [2]string{kept[i], kept[covering]})
+		kept = append(kept[:i], kept[i+1:]...)
+	}
+	return kept, removed
+}
+
+func prunedSteps(name, field string, removed [][2]string, subsumes func(general, specific string) bool) []string {
+	var steps []string
+	for _, r := range removed {
+		reason := fmt.Sprintf("covered by '%s'", r[1])
+		if subsumes(r[0], r[1]) {
+			reason = fmt.Sprintf("duplicate of '%s'", r[1])
+		}
+		steps = append(steps, fmt.Sprintf("statement %s: %s entry '%s' removed: %s", name, field, r[0], reason))
+	}
+	return steps
+}
+
+func interfaceList(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
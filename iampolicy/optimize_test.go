@@ -0,0 +1,137 @@
+package iampolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testCatalog = `# test catalog
+sqs:*
+s3:*
+sqs:DeleteMessage
+sqs:DeleteMessageBatch
+sqs:DeleteQueue
+sqs:ReceiveMessage
+sqs:SendMessage
+sqs:SendMessageBatch
+s3:GetObject
+s3:GetObjectAcl
+s3:PutObject
+`
+
+func TestParseCatalog(t *testing.T) {
+	catalog, err := parseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := catalog.expand("sqs:Delete*"); !reflect.DeepEqual(res, []string{"sqs:DeleteMessage", "sqs:DeleteMessageBatch", "sqs:DeleteQueue"}) {
+		t.Errorf("Unexpected expansion of sqs:Delete*: %v", res)
+	}
+	if !catalog.contains("SQS:sendmessage") || catalog.contains("sqs:Send*") {
+		t.Errorf("Expected contains to match concrete actions ignoring case")
+	}
+	if !catalog.covers("s3:Get*") || catalog.covers("ec2:DescribeInstances") || catalog.covers("*") {
+		t.Errorf("Expected covers to accept only catalogued services")
+	}
+
+	partial, err := parseCatalog([]byte("sqs:SendMessage\nsqs:SendMessageBatch\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.covers("sqs:Send*") {
+		t.Errorf("Expected covers to reject services not marked complete")
+	}
+	if res, _ := partial.collapse([]string{"sqs:SendMessage", "sqs:SendMessageBatch"}); len(res) != 2 {
+		t.Errorf("Expected actions of incomplete services not to be collapsed, but got %v", res)
+	}
+
+	if _, err := parseCatalog([]byte("sqs:Send*\n")); err == nil {
+		t.Errorf("Expected non-nil error for wildcard in catalog, got nil")
+	}
+
+	marked, err := parseCatalog([]byte("sqs:ListQueues *\nsqs:SendMessage\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marked.hasResources("sqs:ListQueues") || !marked.hasResources("sqs:SendMessage") || !marked.hasResources("ec2:DescribeInstances") {
+		t.Errorf("Expected only actions followed by * to have no resource-level permissions")
+	}
+	for _, line := range []string{"sqs:ListQueues arn", "sqs:ListQueues * *"} {
+		if _, err := parseCatalog([]byte(line)); err == nil {
+			t.Errorf("Expected non-nil error for '%s', got nil", line)
+		}
+	}
+	if _, err := LoadCatalog(""); err != nil {
+		t.Errorf("Expected embedded catalog to load, got %s", err)
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	catalog, err := parseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := parsePolicy(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Sid": "Send", "Effect": "Allow", "Action": []interface{}{"sqs:SendMessage", "sqs:SendMessageBatch"}, "Resource": "arn:aws:sqs:us-east-1:1:q"},
+			map[string]interface{}{"Sid": "Receive", "Effect": "Allow", "Action": []interface{}{"sqs:ReceiveMessage", "sqs:DeleteMessage", "sqs:DeleteMessageBatch"}, "Resource": "arn:aws:sqs:us-east-1:1:q"},
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": []interface{}{"s3:GetObject", "s3:GetObjectAcl", "s3:GetObject"}, "Resource": []interface{}{"arn:aws:s3:::b/*", "arn:aws:s3:::b/logs/*"}},
+			map[string]interface{}{"Sid": "ReadLogs", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/logs/*"},
+			map[string]interface{}{"Sid": "Secure", "Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*", "Condition": map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}},
+		},
+	}, InlinePolicy)
+
+	optimized, steps := Optimize(policy, catalog)
+	document := optimized.Document
+
+	expectedSteps := []string{
+		"statement Receive merged into statement Send: same Effect, Principal, Condition and Resource",
+		"statement ReadLogs removed: covered by statement Read",
+		"statement Send: Action 'sqs:SendMessage', 'sqs:SendMessageBatch' replaced by 'sqs:Send*': the catalog lists no other action matching it",
+		"statement Send: Action 'sqs:DeleteMessage', 'sqs:DeleteMessageBatch' replaced by 'sqs:DeleteMessage*': the catalog lists no other action matching it",
+		"statement Read: Action 's3:GetObject', 's3:GetObjectAcl', 's3:GetObject' replaced by 's3:Get*': the catalog lists no other action matching it",
+		"statement Read: Resource entry 'arn:aws:s3:::b/logs/*' removed: covered by 'arn:aws:s3:::b/*'",
+	}
+	if !reflect.DeepEqual(steps, expectedSteps) {
+		t.Errorf("Expected steps:\n%q\nbut got:\n%q", expectedSteps, steps)
+	}
+
+	expectedStatements := []interface{}{
+		map[string]interface{}{"Sid": "Send", "Effect": "Allow", "Action": []interface{}{"sqs:Send*", "sqs:ReceiveMessage", "sqs:DeleteMessage*"}, "Resource": []interface{}{"arn:aws:sqs:us-east-1:1:q"}},
+		map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": []interface{}{"s3:Get*"}, "Resource": []interface{}{"arn:aws:s3:::b/*"}},
+		map[string]interface{}{"Sid": "Secure", "Effect": "Allow", "Action": []interface{}{"s3:PutObject"}, "Resource": []interface{}{"arn:aws:s3:::b/*"}, "Condition": map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}},
+	}
+	if !reflect.DeepEqual(document["Statement"], expectedStatements) {
+		t.Errorf("Expected statements:\n%v\nbut got:\n%v", expectedStatements, document["Statement"])
+	}
+	if ok, err := verifyPolicyDocument(document, InlinePolicy); !ok {
+		t.Errorf("Expected optimized policy to verify, got %v", err)
+	}
+}
+
+func TestOptimizeKeepsUncataloguedActions(t *testing.T) {
+	catalog, err := parseCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name     string
+		actions  []string
+		expected []string
+	}{
+		{"UnknownService", []string{"ec2:DescribeInstances", "ec2:DescribeVolumes"}, []string{"ec2:DescribeInstances", "ec2:DescribeVolumes"}},
+		{"MissingAction", []string{"sqs:DeleteMessage", "sqs:DeleteMessageBatch", "sqs:SendMessage"}, []string{"sqs:DeleteMessage*", "sqs:SendMessage"}},
+		{"WholeService", []string{"s3:GetObject", "s3:GetObjectAcl", "s3:PutObject"}, []string{"s3:*"}},
+		{"ExistingWildcard", []string{"s3:Get*", "s3:PutObject"}, []string{"s3:Get*", "s3:PutObject"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _ := catalog.collapse(tc.actions)
+			if !reflect.DeepEqual(res, tc.expected) {
+				t.Errorf("Expected %v, but got %v", tc.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WildcardPolicy decides which Resource values the wildcard-resource rule
+// flags.
+type WildcardPolicy int
+
+const (
+	// WildcardAsterisk flags only a single asterisk, the default.
+	WildcardAsterisk WildcardPolicy = iota
+	// WildcardService also flags ARNs covering every resource of a service,
+	// e.g. "arn:aws:s3:::*" or "arn:aws:sqs:*:*:*".
+	WildcardService
+	// WildcardAny flags every Resource value holding a wildcard.
+	WildcardAny
+)
+
+func (w WildcardPolicy) String() string {
+	switch w {
+	case WildcardService:
+		return "service"
+	case WildcardAny:
+		return "any"
+	default:
+		return "asterisk"
+	}
+}
+
+// ParseWildcardPolicy returns the wildcard policy named asterisk, service or
+// any.
+func ParseWildcardPolicy(name string) (WildcardPolicy, error) {
+	switch strings.ToLower(name) {
+	case "asterisk":
+		return WildcardAsterisk, nil
+	case "service":
+		return WildcardService, nil
+	case "any":
+		return WildcardAny, nil
+	}
+	return WildcardAsterisk, fmt.Errorf("unknown wildcard policy '%s'", name)
+}
+
+// ParsePolicyKind returns the policy kind named inline, managed or trust.
+func ParsePolicyKind(name string) (PolicyKind, error) {
+	switch strings.ToLower(name) {
+	case "inline":
+		return InlinePolicy, nil
+	case "managed":
+		return ManagedPolicy, nil
+	case "trust":
+		return TrustPolicy, nil
+	}
+	return InlinePolicy, fmt.Errorf("unknown policy kind '%s'", name)
+}
+
+// WithPolicyKind verifies the input as the given kind of policy instead of
+// detecting it. Only trust policies may use Principal, so a bare document
+// with a Principal fails as an inline or managed policy.
+func WithPolicyKind(kind PolicyKind) Option {
+	return optionFunc(func(v *Verifier) error {
+		if kind < InlinePolicy || kind > TrustPolicy {
+			return fmt.Errorf("unknown policy kind %d", int(kind))
+		}
+		v.kind = &kind
+		return nil
+	})
+}
+
+// WithAllowedVersions accepts only the given Version values instead of
+// "2012-10-17" and "2008-10-17".
+func WithAllowedVersions(versions ...string) Option {
+	return optionFunc(func(v *Verifier) error {
+		if len(versions) == 0 {
+			return errors.New("no allowed versions")
+		}
+		v.versions = append([]string{}, versions...)
+		return nil
+	})
+}
+
+// WithStrictFields decides whether fields IAM does not define are errors in
+// policy documents and their statements. They are by default; without
+// strict fields they are ignored.
+func WithStrictFields(strict bool) Option {
+	return optionFunc(func(v *Verifier) error {
+		v.ignoreUnknownFields = !strict
+		return nil
+	})
+}
+
+// WithRules runs the given rules instead of the default ones. DefaultRules
+// and LookupRule return the built-in rules.
+func WithRules(rules ...Rule) Option {
+	return optionFunc(func(v *Verifier) error {
+		v.rules = append([]Rule{}, rules...)
+		v.applyWildcardPolicy()
+		return nil
+	})
+}
+
+// WithWildcardPolicy sets which Resource values the wildcard-resource rule
+// flags.
+func WithWildcardPolicy(policy WildcardPolicy) Option {
+	return optionFunc(func(v *Verifier) error {
+		if policy < WildcardAsterisk || policy > WildcardAny {
+			return fmt.Errorf("unknown wildcard policy %d", int(policy))
+		}
+		v.wildcard = &policy
+		v.applyWildcardPolicy()
+		return nil
+	})
+}
+
+// WithSeverityThreshold fails verification on unsuppressed findings of at
+// least the given severity instead of only on errors.
+func WithSeverityThreshold(severity Severity) Option {
+	return optionFunc(func(v *Verifier) error {
+		if severity < SeverityInfo || severity > SeverityError {
+			return fmt.Errorf("unknown severity %d", int(severity))
+		}
+		v.threshold = &severity
+		return nil
+	})
+}
+
+// DefaultRules returns the rules Verify runs when WithRules is not given.
+func DefaultRules() []Rule {
+	return defaultRules()
+}
+
+// LookupRule returns the registered rule with the given ID, including rules
+// that are disabled by default. Declarative rules are only known to the
+// configuration loading them.
+func LookupRule(id string) (Rule, bool) {
+	rule, ok := ruleRegistry[id]
+	return rule, ok
+}
+
+// Apply applies options to the verifier in order.
+func (v *Verifier) Apply(options ...Option) error {
+	for _, option := range options {
+		if err := option.apply(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyWildcardPolicy sets the wildcard policy on the verifier's
+// wildcard-resource rule, whichever of the options came first, including
+// when the configuration changed the rule's severity.
+func (v *Verifier) applyWildcardPolicy() {
+	if v.wildcard == nil {
+		return
+	}
+	for i, rule := range v.rules {
+		switch r := rule.(type) {
+		case wildcardResourceRule:
+			r.wildcard = *v.wildcard
+			v.rules[i] = r
+		case severityRule:
+			if inner, ok := r.Rule.(wildcardResourceRule); ok {
+				inner.wildcard = *v.wildcard
+				r.Rule = inner
+				v.rules[i] = r
+			}
+		}
+	}
+}
+
+// policyKind returns the forced policy kind, if any, or the detected one.
+func (v *Verifier) policyKind(detected PolicyKind) PolicyKind {
+	if v.kind != nil {
+		return *v.kind
+	}
+	return detected
+}
@@ -0,0 +1,228 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyOptions(t *testing.T) {
+	trust := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`
+	bucket := `{"Version": "2012-10-17", "Statement": [{"Sid": "All", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::*"}]}`
+	objects := `{"Version": "2012-10-17", "Statement": [{"Sid": "Objects", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`
+	asterisk := `{"Version": "2012-10-17", "Statement": [{"Sid": "All", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`
+
+	testCases := []struct {
+		name     string
+		input    string
+		options  []Option
+		expected []string
+		err      string
+	}{
+		{
+			name:  "TrustPolicyDetected",
+			input: trust,
+		},
+		{
+			name:    "TrustPolicyAsManagedPolicy",
+			input:   trust,
+			options: []Option{WithPolicyKind(ManagedPolicy)},
+			err:     "Resource field is missing",
+		},
+		{
+			name:    "UnknownPolicyKind",
+			input:   trust,
+			options: []Option{WithPolicyKind(PolicyKind(7))},
+			err:     "unknown policy kind 7",
+		},
+		{
+			name:    "VersionNotAllowed",
+			input:   objects,
+			options: []Option{WithAllowedVersions("2008-10-17")},
+			err:     "Version field is not one of '2008-10-17'",
+		},
+		{
+			name:    "NoAllowedVersions",
+			input:   objects,
+			options: []Option{WithAllowedVersions()},
+			err:     "no allowed versions",
+		},
+		{
+			name:  "UnknownStatementField",
+			input: `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*", "Comment": "read"}]}`,
+			err:   "unexpected field Comment",
+		},
+		{
+			name:    "UnknownFieldsIgnored",
+			input:   `{"Version": "2012-10-17", "Id": "read", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*", "Comment": "read"}]}`,
+			options: []Option{WithStrictFields(false)},
+		},
+		{
+			name:    "UnknownTrustFieldsIgnored",
+			input:   `{"Version": "2012-10-17", "Id": "assume", "Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole", "Comment": "ec2"}]}`,
+			options: []Option{WithStrictFields(false)},
+		},
+		{
+			name:  "UnknownWrapperField",
+			input: `{"PolicyName": "assume", "Description": "ec2", "PolicyDocument": ` + trust + `}`,
+			err:   "unexpected field Description",
+		},
+		{
+			name:    "UnknownWrapperFieldIgnored",
+			input:   `{"PolicyName": "assume", "Description": "ec2", "PolicyDocument": ` + trust + `}`,
+			options: []Option{WithStrictFields(false), WithPolicyKind(TrustPolicy)},
+		},
+		{
+			name:    "OnlyChosenRules",
+			input:   `{"Version": "2012-10-17", "Statement": [{"Sid": "read-all", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+			options: []Option{WithRules(sidFormatRule{})},
+			expected: []string{
+				"[error] sid-format: statement read-all: Sid 'read-all' is not alphanumeric",
+			},
+		},
+		{
+			name:  "ServiceWildcardAllowedByDefault",
+			input: bucket,
+		},
+		{
+			name:    "ServiceWildcard",
+			input:   bucket,
+			options: []Option{WithWildcardPolicy(WildcardService)},
+			expected: []string{
+				"[error] wildcard-resource: statement All: Resource field 'arn:aws:s3:::*' matches every resource of the service",
+			},
+		},
+		{
+			name:    "ServiceWildcardAllowsPrefix",
+			input:   objects,
+			options: []Option{WithWildcardPolicy(WildcardService)},
+		},
+		{
+			name:    "AnyWildcard",
+			input:   objects,
+			options: []Option{WithWildcardPolicy(WildcardAny)},
+			expected: []string{
+				"[error] wildcard-resource: statement Objects: Resource field 'arn:aws:s3:::data/*' contains a wildcard",
+			},
+		},
+		{
+			name:    "WildcardPolicyBeforeRules",
+			input:   objects,
+			options: []Option{WithWildcardPolicy(WildcardAny), WithRules(DefaultRules()...)},
+			expected: []string{
+				"[error] wildcard-resource: statement Objects: Resource field 'arn:aws:s3:::data/*' contains a wildcard",
+			},
+		},
+		{
+			name:    "SingleAsteriskUnderAnyWildcard",
+			input:   asterisk,
+			options: []Option{WithWildcardPolicy(WildcardAny)},
+			expected: []string{
+				"[error] wildcard-resource: statement All: Resource field contains a single asterisk",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Verify([]byte(tc.input), tc.options...)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, but got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(result.Findings) != len(tc.expected) {
+				t.Fatalf("Expected findings %v, but got %v", tc.expected, result.Findings)
+			}
+			for i, finding := range result.Findings {
+				if finding.String() != tc.expected[i] {
+					t.Errorf("Expected finding %q, but got %q", tc.expected[i], finding.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWildcardPolicyWithConfiguredSeverity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verify_iam.yaml")
+	if err := os.WriteFile(path, []byte("rules: {wildcard-resource: {severity: warning}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	objects := []byte(`{"Version": "2012-10-17", "Statement": [{"Sid": "Objects", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`)
+
+	expected := "[warning] wildcard-resource: statement Objects: Resource field 'arn:aws:s3:::data/*' contains a wildcard"
+	for _, options := range [][]Option{
+		{WithConfigFile(path), WithWildcardPolicy(WildcardAny)},
+		{WithWildcardPolicy(WildcardAny), WithConfigFile(path)},
+	} {
+		result, err := Verify(objects, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Findings) != 1 || result.Findings[0].String() != expected {
+			t.Errorf("Expected finding %q, but got %v", expected, result.Findings)
+		}
+	}
+}
+
+func TestConfigFileKeepsEarlierOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verify_iam.yaml")
+	if err := os.WriteFile(path, []byte("rules: {duplicate-entry: {severity: warning}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	duplicate := []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:GetObject"], "Resource": "arn:aws:s3:::data/*"}]}`)
+
+	result, err := Verify(duplicate, WithPolicyKind(ManagedPolicy), WithSeverityThreshold(SeverityWarning), WithConfigFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Kind != ManagedPolicy || result.Passed || len(result.Findings) != 1 {
+		t.Errorf("Expected a failing managed policy with one finding, but got %s, %t and %v", result.Kind, result.Passed, result.Findings)
+	}
+}
+
+func TestSeverityThreshold(t *testing.T) {
+	duplicate := []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:GetObject"], "Resource": "arn:aws:s3:::data/*"}]}`)
+	testCases := []struct {
+		name     string
+		options  []Option
+		expected bool
+	}{
+		{"Default", nil, true},
+		{"Warning", []Option{WithSeverityThreshold(SeverityWarning)}, false},
+		{"Info", []Option{WithSeverityThreshold(SeverityInfo)}, false},
+		{"Error", []Option{WithSeverityThreshold(SeverityError)}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Verify(duplicate, tc.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Findings) != 1 || result.Passed != tc.expected {
+				t.Errorf("Expected one finding and passed %t, but got %v and %t", tc.expected, result.Findings, result.Passed)
+			}
+		})
+	}
+	if _, err := Verify(duplicate, WithSeverityThreshold(Severity(9))); err == nil {
+		t.Error("Expected unknown severity to be rejected")
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	if _, ok := LookupRule("sid-required"); !ok {
+		t.Error("Expected the optional sid-required rule to be registered")
+	}
+	if _, ok := LookupRule("no-such-rule"); ok {
+		t.Error("Expected no-such-rule to be unknown")
+	}
+	for _, rule := range DefaultRules() {
+		if rule.ID() == "sid-required" {
+			t.Error("Expected sid-required not to run by default")
+		}
+	}
+}
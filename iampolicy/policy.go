@@ -0,0 +1,105 @@
+package iampolicy
+
+import "fmt"
+
+// Policy is the parsed form of a policy document that rules are checked
+// against. It is built only from documents that passed validation, so every
+// statement is known to be well-formed.
+type Policy struct {
+	Kind       PolicyKind
+	Version    string
+	Statements []Statement
+	Document   map[string]interface{}
+}
+
+// Statement is one parsed policy statement. Action, Resource and their
+// negated forms are always lists, whether the document used a single string
+// or a list; a statement uses either Action or NotAction, and either
+// Resource or NotResource.
+type Statement struct {
+	Index       int
+	Sid         string
+	Effect      string
+	Principal   interface{}
+	Action      []string
+	NotAction   []string
+	Resource    []string
+	NotResource []string
+	Condition   map[string]interface{}
+	Raw         map[string]interface{}
+}
+
+// Name identifies the statement in messages by its Sid, or by its position
+// when it has none.
+func (s Statement) Name() string {
+	if s.Sid != "" {
+		return s.Sid
+	}
+	return fmt.Sprintf("#%d", s.Index+1)
+}
+
+// UsesNegation reports whether the statement uses NotAction or NotResource.
+// Diff leaves such statements out; Compare handles them.
+func (s Statement) UsesNegation() bool {
+	return s.Raw["NotAction"] != nil || s.Raw["NotResource"] != nil
+}
+
+func parsePolicy(document map[string]interface{}, kind PolicyKind) *Policy {
+	policy := &Policy{Kind: kind, Document: document}
+	policy.Version, _ = document["Version"].(string)
+
+	statements, _ := document["Statement"].([]interface{})
+	for i, statement := range statements {
+		statementMap, _ := statement.(map[string]interface{})
+		parsed := Statement{
+			Index:       i,
+			Principal:   statementMap["Principal"],
+			Action:      stringList(statementMap["Action"]),
+			NotAction:   stringList(statementMap["NotAction"]),
+			Resource:    stringList(statementMap["Resource"]),
+			NotResource: stringList(statementMap["NotResource"]),
+			Raw:         statementMap,
+		}
+		parsed.Sid, _ = statementMap["Sid"].(string)
+		parsed.Effect, _ = statementMap["Effect"].(string)
+		parsed.Condition, _ = statementMap["Condition"].(map[string]interface{})
+		policy.Statements = append(policy.Statements, parsed)
+	}
+
+	return policy
+}
+
+// stringList returns the values of a field that may hold either a single
+// string or a list of strings.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// principalValues returns every principal a statement names, "*" included.
+func principalValues(principal interface{}) []string {
+	switch p := principal.(type) {
+	case string:
+		return []string{p}
+	case map[string]interface{}:
+		var values []string
+		for _, value := range p {
+			values = append(values, stringList(value)...)
+		}
+		return values
+	}
+	return nil
+}
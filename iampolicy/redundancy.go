@@ -0,0 +1,143 @@
+package iampolicy
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func init() {
+	RegisterRule(redundantStatementRule{})
+	RegisterRule(duplicateEntryRule{})
+}
+
+// redundantStatementRule flags statements whose every action and resource
+// is already covered by another statement with the same effect, principal
+// and condition, so removing them does not change the access granted.
+type redundantStatementRule struct{}
+
+func (redundantStatementRule) ID() string { return "redundant-statement" }
+
+func (redundantStatementRule) Description() string {
+	return "Statements must not be fully covered by another statement with the same Effect, Principal and Condition."
+}
+
+func (redundantStatementRule) Severity() Severity { return SeverityWarning }
+
+func (r redundantStatementRule) Check(policy *Policy) []Finding {
+	scopes := statementScopes(policy.Statements)
+	var findings []Finding
+	for i, statement := range policy.Statements {
+		if other, ok := coveringStatement(policy.Statements, scopes, i); ok {
+			findings = append(findings, newFinding(r, statement, fmt.Sprintf("statement is covered by statement %s", other.Name())))
+		}
+	}
+	return findings
+}
+
+// coveringStatement returns a statement other than statements[i] granting
+// everything statements[i] grants. Of two statements covering each other
+// only the earlier one covers the later, so that one of them is kept.
+func coveringStatement(statements []Statement, scopes [][]byte, i int) (Statement, bool) {
+	for j, other := range statements {
+		if i == j || !bytes.Equal(scopes[i], scopes[j]) || !statementCovers(other, statements[i]) {
+			continue
+		}
+		if j > i && statementCovers(statements[i], other) {
+			continue
+		}
+		return other, true
+	}
+	return Statement{}, false
+}
+
+func statementScopes(statements []Statement) [][]byte {
+	scopes := make([][]byte, len(statements))
+	for i, statement := range statements {
+		scopes[i] = statementScope(statement)
+	}
+	return scopes
+}
+
+// statementScope encodes the parts of a statement other than Action and
+// Resource, which two statements must share for one to cover the other.
+// Statements using NotAction or NotResource are only compared with
+// statements excluding the same actions or resources.
+func statementScope(statement Statement) []byte {
+	normalized := normalizeStatement(statement.Raw)
+	scope := map[string]interface{}{}
+	for _, key := range []string{"Effect", "Principal", "NotPrincipal", "NotAction", "NotResource", "Condition"} {
+		if value, ok := normalized[key]; ok {
+			scope[key] = value
+		}
+	}
+	return minifiedJSON(scope)
+}
+
+// statementCovers reports whether every action and resource of specific is
+// matched by an action and resource of general.
+func statementCovers(general, specific Statement) bool {
+	return listCovers(general.Action, specific.Action, actionSubsumes) &&
+		listCovers(general.Resource, specific.Resource, globSubsumes)
+}
+
+func listCovers(general, specific []string, subsumes func(general, specific string) bool) bool {
+	for _, value := range specific {
+		if coveringEntry(general, value, -1, subsumes) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// coveringEntry returns the index of the first entry of list other than skip
+// that subsumes value, or -1.
+func coveringEntry(list []string, value string, skip int, subsumes func(general, specific string) bool) int {
+	for i, entry := range list {
+		if i != skip && subsumes(entry, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// duplicateEntryRule flags Action and Resource entries that are repeated or
+// already matched by a wildcard entry of the same list.
+type duplicateEntryRule struct{}
+
+func (duplicateEntryRule) ID() string { return "duplicate-entry" }
+
+func (duplicateEntryRule) Description() string {
+	return "Action and Resource lists must not contain entries covered by another entry of the same list."
+}
+
+func (duplicateEntryRule) Severity() Severity { return SeverityWarning }
+
+func (r duplicateEntryRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		findings = append(findings, r.checkList(statement, "Action", statement.Action, actionSubsumes)...)
+		findings = append(findings, r.checkList(statement, "Resource", statement.Resource, globSubsumes)...)
+	}
+	return findings
+}
+
+func (r duplicateEntryRule) checkList(statement Statement, field string, list []string, subsumes func(general, specific string) bool) []Finding {
+	var findings []Finding
+	for i, value := range list {
+		for j, other := range list {
+			if i == j || !subsumes(other, value) {
+				continue
+			}
+			if !subsumes(value, other) {
+				findings = append(findings, newFinding(r, statement, fmt.Sprintf("%s field entry '%s' is covered by '%s'", field, value, other)))
+				break
+			}
+			// Of equivalent entries every occurrence but the first is reported.
+			if j < i {
+				findings = append(findings, newFinding(r, statement, fmt.Sprintf("%s field contains '%s' more than once", field, value)))
+				break
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,81 @@
+package iampolicy
+
+import "testing"
+
+func TestRedundancyRules(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statements []interface{}
+		expected   []string
+	}{
+		{
+			name: "CoveredByWildcard",
+			statements: []interface{}{
+				map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/logs/*"},
+				map[string]interface{}{"Sid": "ReadAll", "Effect": "Allow", "Action": []interface{}{"s3:Get*", "s3:List*"}, "Resource": "arn:aws:s3:::bucket/*"},
+			},
+			expected: []string{"[warning] redundant-statement: statement Read: statement is covered by statement ReadAll"},
+		},
+		{
+			name: "IdenticalStatements",
+			statements: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"s3:GetObject", "s3:PutObject"}, "Resource": "arn:aws:s3:::bucket/*"},
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"S3:PutObject", "s3:GetObject"}, "Resource": "arn:aws:s3:::bucket/*"},
+			},
+			expected: []string{"[warning] redundant-statement: statement #2: statement is covered by statement #1"},
+		},
+		{
+			name: "DifferentCondition",
+			statements: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*", "Condition": map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}},
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket/*"},
+			},
+			expected: nil,
+		},
+		{
+			name: "DifferentEffect",
+			statements: []interface{}{
+				map[string]interface{}{"Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::bucket/*"},
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket/*"},
+			},
+			expected: nil,
+		},
+		{
+			name: "PartialOverlap",
+			statements: []interface{}{
+				map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"s3:GetObject", "sqs:SendMessage"}, "Resource": "arn:aws:s3:::bucket/*"},
+				map[string]interface{}{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket/*"},
+			},
+			expected: nil,
+		},
+		{
+			name: "DuplicateEntries",
+			statements: []interface{}{
+				map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": []interface{}{"s3:GetObject", "s3:Get*", "s3:get*"}, "Resource": []interface{}{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/*"}},
+			},
+			expected: []string{
+				"[warning] duplicate-entry: statement Read: Action field entry 's3:GetObject' is covered by 's3:Get*'",
+				"[warning] duplicate-entry: statement Read: Action field contains 's3:get*' more than once",
+				"[warning] duplicate-entry: statement Read: Resource field contains 'arn:aws:s3:::bucket/*' more than once",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &Verifier{rules: []Rule{redundantStatementRule{}, duplicateEntryRule{}}}
+			findings, err := v.verifyPolicyDocument(map[string]interface{}{"Version": "2012-10-17", "Statement": tc.statements}, InlinePolicy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != len(tc.expected) {
+				t.Fatalf("Expected findings %v, but got %v", tc.expected, findings)
+			}
+			for i, finding := range findings {
+				if finding.String() != tc.expected[i] {
+					t.Errorf("Expected finding '%s', but got '%s'", tc.expected[i], finding)
+				}
+			}
+		})
+	}
+}
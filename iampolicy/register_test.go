@@ -0,0 +1,80 @@
+package iampolicy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"test3/iampolicy"
+)
+
+// bucketPrefixRule is an organisation-specific rule requiring S3 resources
+// to be in buckets with a given prefix.
+type bucketPrefixRule struct {
+	prefix string
+}
+
+func (bucketPrefixRule) ID() string { return "test-bucket-prefix" }
+
+func (bucketPrefixRule) Description() string {
+	return "S3 resources must be in buckets with the team prefix."
+}
+
+func (bucketPrefixRule) Severity() iampolicy.Severity { return iampolicy.SeverityWarning }
+
+func (r bucketPrefixRule) Configure(options map[string]interface{}) (iampolicy.Rule, error) {
+	prefix, ok := options["prefix"].(string)
+	if !ok {
+		return nil, errors.New("option prefix is not a string")
+	}
+	return bucketPrefixRule{prefix: prefix}, nil
+}
+
+func (r bucketPrefixRule) Check(policy *iampolicy.Policy) []iampolicy.Finding {
+	var findings []iampolicy.Finding
+	for _, statement := range policy.Statements {
+		for _, resource := range statement.Resource {
+			if strings.HasPrefix(resource, "arn:aws:s3:::") && !strings.HasPrefix(resource, "arn:aws:s3:::"+r.prefix) {
+				findings = append(findings, iampolicy.Finding{
+					RuleID:    r.ID(),
+					Severity:  r.Severity(),
+					Message:   "resource '" + resource + "' is not in a bucket starting with '" + r.prefix + "'",
+					Statement: statement.Index,
+					Sid:       statement.Sid,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func TestRegisterOptionalRule(t *testing.T) {
+	if _, ok := iampolicy.LookupRule("test-bucket-prefix"); !ok {
+		iampolicy.RegisterOptionalRule(bucketPrefixRule{})
+	}
+	policy := []byte(`{"Version": "2012-10-17", "Statement": [{"Sid": "Data", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`)
+
+	result, err := iampolicy.Verify(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Findings) != 0 {
+		t.Errorf("Expected the optional rule not to run by default, but got %v", result.Findings)
+	}
+
+	path := filepath.Join(t.TempDir(), "verify_iam.yaml")
+	config := "rules: {test-bucket-prefix: {enabled: true, severity: error, options: {prefix: team-}}}"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = iampolicy.Verify(policy, iampolicy.WithConfigFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[error] test-bucket-prefix: statement Data: resource 'arn:aws:s3:::data/*' is not in a bucket starting with 'team-'"
+	if result.Passed || len(result.Findings) != 1 || result.Findings[0].String() != expected {
+		t.Errorf("Expected failing finding %q, but got %t, %v", expected, result.Passed, result.Findings)
+	}
+}
@@ -0,0 +1,106 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// FileReport is the outcome of verifying one input file. Depending on the
+// input it holds the findings of a single policy, the results of the
+// policies of a Terraform plan or those of the roles of an account
+// authorization details snapshot.
+type FileReport struct {
+	File      string         `json:"file"`
+	Passed    bool           `json:"passed"`
+	Kind      string         `json:"kind,omitempty"`
+	Findings  []Finding      `json:"findings,omitempty"`
+	Policies  []PolicyResult `json:"policies,omitempty"`
+	Roles     []RoleResult   `json:"roles,omitempty"`
+	Expired   []Suppression  `json:"expiredSuppressions,omitempty"`
+	Baselined int            `json:"baselined,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// VerifyFile verifies an input file with the suppressions of its sidecar
+// annotation file added.
+func (v *Verifier) VerifyFile(jsonFile string) (*FileReport, error) {
+	fileData, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	v, err = v.ForFile(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	return v.verifyInput(jsonFile, fileData)
+}
+
+// VerifyInput verifies the content of an input file: a single policy, a
+// Terraform plan or an account authorization details snapshot. The name
+// identifies the input in the report and the baseline.
+func (v *Verifier) VerifyInput(name string, fileData []byte) (*FileReport, error) {
+	return v.named(name).verifyInput(name, fileData)
+}
+
+func (v *Verifier) verifyInput(jsonFile string, fileData []byte) (*FileReport, error) {
+	report := &FileReport{File: jsonFile, Expired: v.ExpiredSuppressions()}
+	input, err := parseInput(fileData)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := input.(map[string]interface{})
+	switch {
+	case isTerraformPlan(data):
+		report.Policies, err = v.verifyTerraformPlan(fileData)
+		if err != nil {
+			return nil, err
+		}
+		report.Passed = policyResultsPassed(report.Policies)
+	case isAuthorizationDetails(data):
+		report.Roles, err = v.verifyAuthorizationDetails(fileData)
+		if err != nil {
+			return nil, err
+		}
+		report.Passed = true
+		for _, role := range report.Roles {
+			report.Passed = report.Passed && policyResultsPassed(role.Results)
+		}
+	default:
+		normalized, kind, err := normalizePolicyInput(input)
+		if err != nil {
+			return nil, err
+		}
+		kind = v.policyKind(kind)
+		report.Findings, err = v.verifyPolicyInput(normalized, kind)
+		if err != nil {
+			return nil, err
+		}
+		report.Kind = kind.String()
+		report.Passed = v.passed(report.Findings)
+	}
+	report.Passed = report.Passed && len(report.Expired) == 0
+	report.Baselined = v.baselined
+	return report, nil
+}
+
+func policyResultsPassed(results []PolicyResult) bool {
+	for _, result := range results {
+		if result.Err != nil || !result.Result {
+			return false
+		}
+	}
+	return true
+}
+
+func (r PolicyResult) MarshalJSON() ([]byte, error) {
+	type plain PolicyResult
+	var message string
+	if r.Err != nil {
+		message = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain(r), message})
+}
@@ -0,0 +1,55 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyFile(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"Version": "2012-10-17", "Statement": [{"Principal": {"Service": "ec2.amazonaws.com"}, "Effect": "Allow", "Action": "sts:AssumeRole"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan := filepath.Join(dir, "plan.json")
+	if err := os.WriteFile(plan, []byte(terraformPlanJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := newVerifier().VerifyFile(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed || report.Kind != "trust" || len(report.Findings) != 0 || report.Policies != nil {
+		t.Errorf("Unexpected report for a trust policy: %+v", report)
+	}
+
+	report, err = newVerifier().VerifyFile(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed || report.Kind != "" || len(report.Policies) == 0 {
+		t.Errorf("Unexpected report for a Terraform plan: %+v", report)
+	}
+
+	if _, err := newVerifier().VerifyFile(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected missing file error, but got %v", err)
+	}
+}
+
+func TestPolicyResultJSON(t *testing.T) {
+	out, err := json.Marshal([]PolicyResult{
+		{Source: "aws_iam_role.app", Result: true},
+		{Source: "aws_iam_policy.bad", Err: errors.New("Effect field is missing")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"source":"aws_iam_role.app","passed":true},{"source":"aws_iam_policy.bad","passed":false,"error":"Effect field is missing"}]`
+	if string(out) != expected {
+		t.Errorf("Expected %s, but got %s", expected, out)
+	}
+}
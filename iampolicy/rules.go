@@ -0,0 +1,389 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// ParseSeverity returns the severity named info, warning or error.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityError, fmt.Errorf("unknown severity '%s'", name)
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Finding is a problem a rule found in a policy. Statement is the index of
+// the offending statement, or -1 when the finding is about the whole document.
+type Finding struct {
+	RuleID    string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Statement int      `json:"statement"`
+	Sid       string   `json:"sid,omitempty"`
+	// Suppression is set when the finding was silenced by a suppression;
+	// suppressed findings never fail verification.
+	Suppression *Suppression `json:"suppression,omitempty"`
+}
+
+func (f Finding) String() string {
+	location := "policy"
+	if f.Sid != "" {
+		location = fmt.Sprintf("statement %s", f.Sid)
+	} else if f.Statement >= 0 {
+		location = fmt.Sprintf("statement #%d", f.Statement+1)
+	}
+	return fmt.Sprintf("[%s] %s: %s: %s", f.Severity, f.RuleID, location, f.Message)
+}
+
+// Rule is a single check run against every policy that passed validation.
+// Rules that only apply to some kinds of policies return no findings for
+// the others.
+type Rule interface {
+	ID() string
+	Description() string
+	Severity() Severity
+	Check(policy *Policy) []Finding
+}
+
+var ruleRegistry = map[string]Rule{}
+
+// optionalRules are registered but only run when enabled in the
+// configuration file.
+var optionalRules = map[string]bool{}
+
+// RegisterRule adds a rule to the set every verifier runs by default, which
+// the configuration file can disable or give another severity. Rules with a
+// Configure(options map[string]interface{}) (Rule, error) method also take
+// options from it. Organisation-specific rules register themselves from an
+// init function. Registering two rules with the same ID panics.
+func RegisterRule(rule Rule) {
+	if _, ok := ruleRegistry[rule.ID()]; ok {
+		panic("rule " + rule.ID() + " registered twice")
+	}
+	ruleRegistry[rule.ID()] = rule
+}
+
+// RegisterOptionalRule registers a rule that only runs when the
+// configuration file enables it.
+func RegisterOptionalRule(rule Rule) {
+	RegisterRule(rule)
+	optionalRules[rule.ID()] = true
+}
+
+// defaultRules returns the registered rules that are enabled by default.
+func defaultRules() []Rule {
+	var rules []Rule
+	for _, rule := range registeredRules() {
+		if !optionalRules[rule.ID()] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// registeredRules returns all registered rules, and the given declarative
+// ones, ordered by ID.
+func registeredRules(declared ...Rule) []Rule {
+	rules := make([]Rule, 0, len(ruleRegistry)+len(declared))
+	for _, rule := range ruleRegistry {
+		rules = append(rules, rule)
+	}
+	rules = append(rules, declared...)
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID() < rules[j].ID()
+	})
+	return rules
+}
+
+// lookupRule returns the declarative or registered rule with the given ID.
+func lookupRule(declared []Rule, id string) (Rule, bool) {
+	for _, rule := range declared {
+		if rule.ID() == id {
+			return rule, true
+		}
+	}
+	rule, ok := ruleRegistry[id]
+	return rule, ok
+}
+
+// newFinding creates a finding of the rule's severity for a statement.
+func newFinding(rule Rule, statement Statement, message string) Finding {
+	return Finding{
+		RuleID:    rule.ID(),
+		Severity:  rule.Severity(),
+		Message:   message,
+		Statement: statement.Index,
+		Sid:       statement.Sid,
+	}
+}
+
+func init() {
+	RegisterRule(wildcardResourceRule{})
+	RegisterRule(allowNotActionRule{})
+	RegisterRule(wildcardPrincipalRule{})
+	RegisterRule(sidFormatRule{})
+	RegisterRule(sidUniqueRule{})
+	RegisterOptionalRule(sidRequiredRule{})
+}
+
+// wildcardResourceRule flags statements granting access to every resource.
+// Statements whose actions all match one of allowedActions are exempt, as
+// some actions, e.g. ec2:DescribeInstances, only work on "*". The wildcard
+// policy decides which other Resource values count as a wildcard. Allow
+// statements using NotResource are always flagged: they grant everything
+// but the listed resources.
+type wildcardResourceRule struct {
+	allowedActions []string
+	wildcard       WildcardPolicy
+}
+
+func (wildcardResourceRule) ID() string { return "wildcard-resource" }
+
+func (wildcardResourceRule) Description() string {
+	return "Permission policy statements must not use a single asterisk as Resource, nor allow with NotResource."
+}
+
+func (wildcardResourceRule) Severity() Severity { return SeverityError }
+
+func (r wildcardResourceRule) Configure(options map[string]interface{}) (Rule, error) {
+	if err := checkOptions(options, "allowedActions", "wildcard"); err != nil {
+		return nil, err
+	}
+	allowedActions, err := optionStrings(options, "allowedActions")
+	if err != nil {
+		return nil, err
+	}
+	configured := wildcardResourceRule{allowedActions: allowedActions, wildcard: r.wildcard}
+	if value, ok := options["wildcard"]; ok {
+		name, ok := value.(string)
+		if !ok {
+			return nil, errors.New("option wildcard is not a string")
+		}
+		configured.wildcard, err = ParseWildcardPolicy(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return configured, nil
+}
+
+// wildcardMessage describes why a Resource value counts as a wildcard, or
+// returns an empty string when it does not.
+func (r wildcardResourceRule) wildcardMessage(resource string) string {
+	if resource == "*" {
+		return "Resource field contains a single asterisk"
+	}
+	switch r.wildcard {
+	case WildcardService:
+		fields := strings.SplitN(resource, ":", 6)
+		if len(fields) == 6 && strings.Trim(fields[5], "*/:") == "" {
+			return "Resource field '" + resource + "' matches every resource of the service"
+		}
+	case WildcardAny:
+		if strings.ContainsAny(resource, "*?") {
+			return "Resource field '" + resource + "' contains a wildcard"
+		}
+	}
+	return ""
+}
+
+func (r wildcardResourceRule) allowed(statement Statement) bool {
+	if len(r.allowedActions) == 0 || len(statement.Action) == 0 {
+		return false
+	}
+	for _, action := range statement.Action {
+		matched := false
+		for _, pattern := range r.allowedActions {
+			if actionMatch(pattern, action) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (r wildcardResourceRule) Check(policy *Policy) []Finding {
+	if policy.Kind == TrustPolicy {
+		return nil
+	}
+
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		if statement.Effect == "Allow" && len(statement.NotResource) > 0 {
+			findings = append(findings, newFinding(r, statement, "NotResource field allows every resource except those listed"))
+			continue
+		}
+		if r.allowed(statement) {
+			continue
+		}
+		for _, resource := range statement.Resource {
+			if message := r.wildcardMessage(resource); message != "" {
+				findings = append(findings, newFinding(r, statement, message))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// allowNotActionRule flags Allow statements using NotAction: they grant
+// every action but the listed ones, including actions added to AWS later.
+type allowNotActionRule struct{}
+
+func (allowNotActionRule) ID() string { return "allow-not-action" }
+
+func (allowNotActionRule) Description() string {
+	return "Statements must not allow with NotAction."
+}
+
+func (allowNotActionRule) Severity() Severity { return SeverityError }
+
+func (r allowNotActionRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		if statement.Effect == "Allow" && len(statement.NotAction) > 0 {
+			findings = append(findings, newFinding(r, statement, "NotAction field allows every action except those listed"))
+		}
+	}
+	return findings
+}
+
+type wildcardPrincipalRule struct{}
+
+func (wildcardPrincipalRule) ID() string { return "wildcard-principal" }
+
+func (wildcardPrincipalRule) Description() string {
+	return "Trust policy statements must not let any principal assume the role."
+}
+
+func (wildcardPrincipalRule) Severity() Severity { return SeverityError }
+
+func (r wildcardPrincipalRule) Check(policy *Policy) []Finding {
+	if policy.Kind != TrustPolicy {
+		return nil
+	}
+
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		for _, principal := range principalValues(statement.Principal) {
+			if principal == "*" {
+				findings = append(findings, newFinding(r, statement, "Principal field contains a single asterisk"))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+var sidPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+type sidFormatRule struct{}
+
+func (sidFormatRule) ID() string { return "sid-format" }
+
+func (sidFormatRule) Description() string {
+	return "Statement Sids must contain only ASCII letters and digits."
+}
+
+func (sidFormatRule) Severity() Severity { return SeverityError }
+
+func (r sidFormatRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		if _, ok := statement.Raw["Sid"]; ok && !sidPattern.MatchString(statement.Sid) {
+			findings = append(findings, newFinding(r, statement, fmt.Sprintf("Sid '%s' is not alphanumeric", statement.Sid)))
+		}
+	}
+	return findings
+}
+
+type sidUniqueRule struct{}
+
+func (sidUniqueRule) ID() string { return "sid-unique" }
+
+func (sidUniqueRule) Description() string {
+	return "Statement Sids must be unique within a policy."
+}
+
+func (sidUniqueRule) Severity() Severity { return SeverityError }
+
+func (r sidUniqueRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	first := map[string]int{}
+	for _, statement := range policy.Statements {
+		if statement.Sid == "" {
+			continue
+		}
+		if index, ok := first[statement.Sid]; ok {
+			findings = append(findings, newFinding(r, statement, fmt.Sprintf("Sid '%s' is already used by statement #%d", statement.Sid, index+1)))
+			continue
+		}
+		first[statement.Sid] = statement.Index
+	}
+	return findings
+}
+
+type sidRequiredRule struct{}
+
+func (sidRequiredRule) ID() string { return "sid-required" }
+
+func (sidRequiredRule) Description() string {
+	return "Every statement must have a Sid."
+}
+
+func (sidRequiredRule) Severity() Severity { return SeverityWarning }
+
+func (r sidRequiredRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		if _, ok := statement.Raw["Sid"]; !ok {
+			findings = append(findings, newFinding(r, statement, "Sid field is missing"))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,257 @@
+package iampolicy
+
+import (
+	"strings"
+	"testing"
+)
+
+type actionPrefixRule struct {
+	prefix string
+}
+
+func (actionPrefixRule) ID() string { return "test-action-prefix" }
+
+func (actionPrefixRule) Description() string { return "Actions must not use a forbidden service." }
+
+func (actionPrefixRule) Severity() Severity { return SeverityWarning }
+
+func (r actionPrefixRule) Check(policy *Policy) []Finding {
+	var findings []Finding
+	for _, statement := range policy.Statements {
+		for _, action := range statement.Action {
+			if strings.HasPrefix(action, r.prefix) {
+				findings = append(findings, newFinding(r, statement, "forbidden action "+action))
+			}
+		}
+	}
+	return findings
+}
+
+func TestWildcardRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document map[string]interface{}
+		kind     PolicyKind
+		expected []string
+	}{
+		{
+			name: "ResourceIsAsterisk",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Sid": "First", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"},
+					map[string]interface{}{"Sid": "Second", "Effect": "Allow", "Action": "s3:ListAllMyBuckets", "Resource": []interface{}{"arn:aws:s3:::bucket", "*"}},
+				},
+			},
+			kind: InlinePolicy,
+			expected: []string{
+				"[warning] duplicate-entry: statement Second: Resource field entry 'arn:aws:s3:::bucket' is covered by '*'",
+				"[error] wildcard-resource: statement Second: Resource field contains a single asterisk",
+			},
+		},
+		{
+			name: "AllowWithNotResource",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Sid": "Everything", "Effect": "Allow", "Action": "*", "NotResource": "arn:aws:s3:::nothing"},
+				},
+			},
+			kind:     InlinePolicy,
+			expected: []string{"[error] wildcard-resource: statement Everything: NotResource field allows every resource except those listed"},
+		},
+		{
+			name: "AllowWithNotAction",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Sid": "AllButIam", "Effect": "Allow", "NotAction": "iam:*", "Resource": "arn:aws:s3:::bucket/*"},
+				},
+			},
+			kind:     InlinePolicy,
+			expected: []string{"[error] allow-not-action: statement AllButIam: NotAction field allows every action except those listed"},
+		},
+		{
+			name: "AllowWithNotActionOnAsterisk",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Sid": "AllButIam", "Effect": "Allow", "NotAction": "iam:*", "Resource": "*"},
+				},
+			},
+			kind: InlinePolicy,
+			expected: []string{
+				"[error] allow-not-action: statement AllButIam: NotAction field allows every action except those listed",
+				"[error] wildcard-resource: statement AllButIam: Resource field contains a single asterisk",
+			},
+		},
+		{
+			name: "DenyWithNotActionAndNotResource",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Sid": "Guard", "Effect": "Deny", "NotAction": "iam:*", "NotResource": "arn:aws:s3:::bucket/*"},
+				},
+			},
+			kind:     InlinePolicy,
+			expected: nil,
+		},
+		{
+			name: "PrincipalIsAsterisk",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"AWS": []interface{}{"arn:aws:iam::123456789012:root", "*"}}},
+				},
+			},
+			kind:     TrustPolicy,
+			expected: []string{"[error] wildcard-principal: statement #1: Principal field contains a single asterisk"},
+		},
+		{
+			name: "NoWildcards",
+			document: map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": map[string]interface{}{"Service": "ec2.amazonaws.com"}},
+				},
+			},
+			kind:     TrustPolicy,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, err := newVerifier().verifyPolicyDocument(tc.document, tc.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(findings) != len(tc.expected) {
+				t.Fatalf("Expected findings %v, but got %v", tc.expected, findings)
+			}
+			for i, finding := range findings {
+				if finding.String() != tc.expected[i] {
+					t.Errorf("Expected finding '%s', but got '%s'", tc.expected[i], finding)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowNotActionRule(t *testing.T) {
+	document := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Sid": "Objects", "Effect": "Allow", "NotAction": "s3:DeleteObject", "Resource": "arn:aws:s3:::bucket/*"},
+			map[string]interface{}{"Sid": "Guard", "Effect": "Deny", "NotAction": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/secret/*"},
+			map[string]interface{}{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"},
+		},
+	}
+
+	findings, err := newVerifier().verifyPolicyDocument(document, InlinePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[error] allow-not-action: statement Objects: NotAction field allows every action except those listed"
+	if len(findings) != 1 || findings[0].String() != expected {
+		t.Errorf("Expected only finding %q, but got %v", expected, findings)
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	v := &Verifier{rules: []Rule{wildcardResourceRule{}, actionPrefixRule{prefix: "iam:"}}}
+	findings, err := v.verifyPolicyDocument(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Effect": "Allow", "Action": []interface{}{"iam:ListRoles", "s3:GetObject"}, "Resource": "arn:aws:s3:::bucket/*"},
+		},
+	}, InlinePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 1 || findings[0].RuleID != "test-action-prefix" || findings[0].Message != "forbidden action iam:ListRoles" {
+		t.Fatalf("Expected one test-action-prefix finding, but got %v", findings)
+	}
+	if !passed(findings) {
+		t.Errorf("Expected warnings not to fail verification")
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	RegisterRule(actionPrefixRule{prefix: "iam:"})
+	defer delete(ruleRegistry, "test-action-prefix")
+
+	found := false
+	for _, rule := range registeredRules() {
+		if rule.ID() == "test-action-prefix" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected registered rule to be returned by registeredRules")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering a rule twice to panic")
+		}
+	}()
+	RegisterRule(actionPrefixRule{prefix: "s3:"})
+}
+
+func TestSidRules(t *testing.T) {
+	document := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Sid": "ReadObjects", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"},
+			map[string]interface{}{"Sid": "read-objects", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::other/*"},
+			map[string]interface{}{"Sid": "ReadObjects", "Effect": "Allow", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::bucket"},
+			map[string]interface{}{"Effect": "Allow", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::other"},
+			map[string]interface{}{"Sid": "", "Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::other/*"},
+		},
+	}
+
+	findings, err := newVerifier().verifyPolicyDocument(document, InlinePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"[error] sid-format: statement read-objects: Sid 'read-objects' is not alphanumeric",
+		"[error] sid-format: statement #5: Sid '' is not alphanumeric",
+		"[error] sid-unique: statement ReadObjects: Sid 'ReadObjects' is already used by statement #1",
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected findings %v, but got %v", expected, findings)
+	}
+	for i, finding := range findings {
+		if finding.String() != expected[i] {
+			t.Errorf("Expected finding '%s', but got '%s'", expected[i], finding)
+		}
+	}
+
+	cfg, err := LoadConfig(writeConfig(t, "rules: {sid-required: {enabled: true}}"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := cfg.Verifier("policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err = v.verifyPolicyDocument(document, InlinePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	required := 0
+	for _, finding := range findings {
+		if finding.RuleID == "sid-required" {
+			required++
+			if finding.Statement != 3 || finding.Severity != SeverityWarning {
+				t.Errorf("Expected sid-required warning on statement #4, but got %s", finding)
+			}
+		}
+	}
+	if required != 1 {
+		t.Errorf("Expected one sid-required finding, but got %d", required)
+	}
+}
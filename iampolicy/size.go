@@ -0,0 +1,113 @@
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// policySizeLimits are the IAM quotas on policy size, in characters after
+// whitespace removal. The trust policy limit can be raised to 4,096 through
+// a quota increase, which the maxTrust option accounts for.
+var policySizeLimits = map[PolicyKind]int{
+	InlinePolicy:  10240,
+	ManagedPolicy: 6144,
+	TrustPolicy:   2048,
+}
+
+// minifiedJSON encodes a value without whitespace and without escaping
+// HTML characters, the way IAM measures policy size.
+func minifiedJSON(value interface{}) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+func minifiedSize(value interface{}) int {
+	return utf8.RuneCount(minifiedJSON(value))
+}
+
+// Size returns the size of the policy document as IAM counts it against its
+// quotas: in characters, after whitespace removal.
+func (p *Policy) Size() int {
+	return minifiedSize(p.Document)
+}
+
+func init() {
+	RegisterRule(policySizeRule{limits: policySizeLimits})
+}
+
+type policySizeRule struct {
+	limits map[PolicyKind]int
+}
+
+func (policySizeRule) ID() string { return "policy-size" }
+
+func (policySizeRule) Description() string {
+	return "Policies must fit the IAM size quota of their kind: 10,240 characters for inline, 6,144 for managed and 2,048 for trust policies."
+}
+
+func (policySizeRule) Severity() Severity { return SeverityError }
+
+func (r policySizeRule) Configure(options map[string]interface{}) (Rule, error) {
+	if err := checkOptions(options, "maxInline", "maxManaged", "maxTrust"); err != nil {
+		return nil, err
+	}
+	limits := map[PolicyKind]int{}
+	for kind, limit := range r.limits {
+		limits[kind] = limit
+	}
+	for key, kind := range map[string]PolicyKind{"maxInline": InlinePolicy, "maxManaged": ManagedPolicy, "maxTrust": TrustPolicy} {
+		value, ok := options[key]
+		if !ok {
+			continue
+		}
+		limit, ok := value.(int)
+		if !ok || limit <= 0 {
+			return nil, fmt.Errorf("option %s is not a positive integer", key)
+		}
+		limits[kind] = limit
+	}
+	return policySizeRule{limits: limits}, nil
+}
+
+func (r policySizeRule) Check(policy *Policy) []Finding {
+	limit := r.limits[policy.Kind]
+	size := minifiedSize(policy.Document)
+	if limit == 0 || size <= limit {
+		return nil
+	}
+
+	type contribution struct {
+		statement Statement
+		size      int
+	}
+	contributions := make([]contribution, 0, len(policy.Statements))
+	for _, statement := range policy.Statements {
+		contributions = append(contributions, contribution{statement, minifiedSize(statement.Raw)})
+	}
+	sort.SliceStable(contributions, func(i, j int) bool {
+		return contributions[i].size > contributions[j].size
+	})
+	if len(contributions) > 3 {
+		contributions = contributions[:3]
+	}
+	largest := make([]string, 0, len(contributions))
+	for _, c := range contributions {
+		largest = append(largest, fmt.Sprintf("%s (%d)", c.statement.Name(), c.size))
+	}
+
+	return []Finding{{
+		RuleID:    r.ID(),
+		Severity:  r.Severity(),
+		Message:   fmt.Sprintf("%s policy is %d characters after whitespace removal, over the %d limit; largest statements: %s", policy.Kind, size, limit, strings.Join(largest, ", ")),
+		Statement: -1,
+	}}
+}
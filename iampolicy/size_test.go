@@ -0,0 +1,100 @@
+package iampolicy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func sizedPolicy(statements int, resourceLength int) map[string]interface{} {
+	var list []interface{}
+	for i := 0; i < statements; i++ {
+		list = append(list, map[string]interface{}{
+			"Sid":      fmt.Sprintf("Statement%d", i),
+			"Effect":   "Allow",
+			"Action":   "s3:GetObject",
+			"Resource": "arn:aws:s3:::" + strings.Repeat("b", resourceLength+i),
+		})
+	}
+	return map[string]interface{}{"Version": "2012-10-17", "Statement": list}
+}
+
+func TestMinifiedSize(t *testing.T) {
+	document := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::a&b/<c>"},
+		},
+	}
+	expected := `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"arn:aws:s3:::a&b/<c>"}],"Version":"2012-10-17"}`
+	if res := string(minifiedJSON(document)); res != expected {
+		t.Errorf("Expected '%s', but got '%s'", expected, res)
+	}
+	if res := minifiedSize(document); res != len(expected) {
+		t.Errorf("Expected size %d, but got %d", len(expected), res)
+	}
+}
+
+func TestPolicySizeRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document map[string]interface{}
+		kind     PolicyKind
+		expected string
+	}{
+		{
+			name:     "InlineUnderLimit",
+			document: sizedPolicy(40, 100),
+			kind:     InlinePolicy,
+		},
+		{
+			name:     "ManagedOverLimit",
+			document: sizedPolicy(40, 100),
+			kind:     ManagedPolicy,
+			expected: "[error] policy-size: policy: managed policy is 8408 characters after whitespace removal, over the 6144 limit; largest statements: Statement39 (228), Statement38 (227), Statement37 (226)",
+		},
+		{
+			name:     "InlineOverLimit",
+			document: sizedPolicy(2, 10300),
+			kind:     InlinePolicy,
+			expected: "[error] policy-size: policy: inline policy is 20817 characters after whitespace removal, over the 10240 limit; largest statements: Statement1 (10389), Statement0 (10388)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := policySizeRule{limits: policySizeLimits}.Check(parsePolicy(tc.document, tc.kind))
+			if tc.expected == "" {
+				if len(findings) != 0 {
+					t.Errorf("Expected no findings, but got %v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].String() != tc.expected {
+				t.Errorf("Expected finding '%s', but got %v", tc.expected, findings)
+			}
+		})
+	}
+}
+
+func TestPolicySizeRuleOptions(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, "rules: {policy-size: {options: {maxManaged: 10000}}}"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := cfg.Verifier("policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := v.verifyPolicyDocument(sizedPolicy(40, 100), ManagedPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected raised limit to accept policy, but got %v", findings)
+	}
+
+	if _, err := LoadConfig(writeConfig(t, "rules: {policy-size: {options: {maxTrust: big}}}"), ""); err == nil {
+		t.Errorf("Expected non-nil error for invalid limit, got nil")
+	}
+}
@@ -0,0 +1,112 @@
+package iampolicy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"unicode"
+)
+
+// streamRecord is one policy read from a stream of policies.
+type streamRecord struct {
+	index int
+	data  json.RawMessage
+}
+
+// RecordResult is the outcome of verifying one streamed policy. Index is
+// the position of the policy in the stream, from 0.
+type RecordResult struct {
+	Index  int
+	Result *Result
+	Err    error
+}
+
+// decodeRecords reads policies one at a time from either a JSON array or
+// newline-delimited JSON and sends them to records, so only the records
+// being verified are held in memory.
+func decodeRecords(r io.Reader, records chan<- streamRecord) error {
+	reader := bufio.NewReader(r)
+	var first byte
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			first = b
+			break
+		}
+	}
+	if err := reader.UnreadByte(); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(reader)
+	array := first == '['
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+	for index := 0; !array || decoder.More(); index++ {
+		var data json.RawMessage
+		err := decoder.Decode(&data)
+		if err == io.EOF && !array {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("record #%d: %s", index+1, err)
+		}
+		records <- streamRecord{index: index, data: data}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyStream verifies the policies read from r, either a JSON array or
+// newline-delimited JSON, with the given number of workers and reports each
+// result as it completes, in no particular order. The workers share v, so
+// it returns an error when v has a baseline or records one.
+func (v *Verifier) VerifyStream(r io.Reader, workers int, report func(RecordResult)) error {
+	if workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+	if v.baseline != nil || v.record != nil {
+		return errors.New("baselines cannot be used when verifying a stream")
+	}
+	records := make(chan streamRecord, workers)
+	results := make(chan RecordResult, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range records {
+				result, err := v.Verify(record.data)
+				results <- RecordResult{Index: record.index, Result: result, Err: err}
+			}
+		}()
+	}
+
+	var decodeErr error
+	go func() {
+		decodeErr = decodeRecords(r, records)
+		close(records)
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		report(result)
+	}
+	return decodeErr
+}
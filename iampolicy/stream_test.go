@@ -0,0 +1,113 @@
+package iampolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	streamPassing = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`
+	streamFailing = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`
+	streamInvalid = `{"Version": "2012-10-17", "Statement": [{"Action": "s3:GetObject", "Resource": "*"}]}`
+)
+
+func TestVerifyStream(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "NDJSON",
+			input:    streamPassing + "\n" + streamFailing + "\n\n" + streamInvalid + "\n",
+			expected: []string{"0 true", "1 false", "2 Effect field is missing"},
+		},
+		{
+			name:     "JSONArray",
+			input:    "\n  [" + streamFailing + ",\n" + streamPassing + "]\n",
+			expected: []string{"0 false", "1 true"},
+		},
+		{
+			name:     "EmptyArray",
+			input:    "[]",
+			expected: nil,
+		},
+		{
+			name:     "Empty",
+			input:    " \n",
+			expected: nil,
+		},
+		{
+			name:     "MultiplePolicies",
+			input:    `{"RoleDetailList": []}` + "\n" + streamPassing,
+			expected: []string{"0 input holds more than one policy", "1 true"},
+		},
+		{
+			name:     "MalformedRecord",
+			input:    streamPassing + "\n{\"Version\": \n",
+			expected: []string{"0 true"},
+			err:      "record #2: unexpected EOF",
+		},
+		{
+			name:     "UnterminatedArray",
+			input:    "[" + streamPassing + ",",
+			expected: []string{"0 true"},
+			err:      "record #2: unexpected end of JSON input",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []string
+			err := newVerifier().VerifyStream(strings.NewReader(tc.input), 3, func(record RecordResult) {
+				if record.Err != nil {
+					results = append(results, fmt.Sprintf("%d %s", record.Index, record.Err))
+				} else {
+					results = append(results, fmt.Sprintf("%d %t", record.Index, record.Result.Passed))
+				}
+			})
+			if tc.err == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tc.err)) {
+				t.Fatalf("Expected error %q, but got %v", tc.err, err)
+			}
+			sort.Strings(results)
+			if strings.Join(results, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("Expected results %v, but got %v", tc.expected, results)
+			}
+		})
+	}
+}
+
+func TestVerifyStreamBaseline(t *testing.T) {
+	input := strings.Repeat(streamFailing+"\n", 20)
+	for _, option := range []Option{WithBaseline(&Baseline{}), WithBaselineRecord(&Baseline{})} {
+		v, err := NewVerifier(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = v.VerifyStream(strings.NewReader(input), 4, func(RecordResult) {})
+		expected := "baselines cannot be used when verifying a stream"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, but got %v", expected, err)
+		}
+	}
+
+	// Run with -race: the workers share the verifier.
+	failed := 0
+	err := newVerifier().VerifyStream(strings.NewReader(input), 4, func(record RecordResult) {
+		if record.Err == nil && !record.Result.Passed {
+			failed++
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 20 {
+		t.Errorf("Expected 20 failing records, but got %d", failed)
+	}
+}
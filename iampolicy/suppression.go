@@ -0,0 +1,133 @@
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// sidecarSuffixes name the annotation files holding the suppressions of a
+// policy file, e.g. role.json.suppressions.yaml next to role.json.
+var sidecarSuffixes = []string{".suppressions.yaml", ".suppressions.yml", ".suppressions.json"}
+
+// Suppression silences the findings of one rule on the statement with the
+// given Sid until it expires, if it has an expiry date (YYYY-MM-DD). Paths
+// restricts suppressions from the configuration file to some input files;
+// it is not used in sidecar files.
+type Suppression struct {
+	Rule    string   `yaml:"rule" json:"rule"`
+	Sid     string   `yaml:"sid" json:"sid"`
+	Paths   []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	Reason  string   `yaml:"reason" json:"reason"`
+	Expires string   `yaml:"expires,omitempty" json:"expires,omitempty"`
+}
+
+type suppressionFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+func (s Suppression) validate() error {
+	if s.Rule == "" {
+		return errors.New("suppression without rule")
+	}
+	if s.Sid == "" {
+		return fmt.Errorf("suppression of %s without sid", s.Rule)
+	}
+	if s.Reason == "" {
+		return fmt.Errorf("suppression of %s on %s without reason", s.Rule, s.Sid)
+	}
+	if s.Expires != "" {
+		if _, err := time.Parse("2006-01-02", s.Expires); err != nil {
+			return fmt.Errorf("suppression of %s on %s has invalid expiry date '%s'", s.Rule, s.Sid, s.Expires)
+		}
+	}
+	return nil
+}
+
+// expired reports whether the suppression's expiry date is before today.
+// A suppression is still valid on the day it expires.
+func (s Suppression) expired(today time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse("2006-01-02", s.Expires)
+	if err != nil {
+		return true
+	}
+	return today.Format("2006-01-02") > expires.Format("2006-01-02")
+}
+
+func (s Suppression) matches(finding Finding) bool {
+	return s.Rule == finding.RuleID && s.Sid != "" && s.Sid == finding.Sid
+}
+
+func (s Suppression) String() string {
+	text := fmt.Sprintf("%s on statement %s: %s", s.Rule, s.Sid, s.Reason)
+	if s.Expires != "" {
+		text += fmt.Sprintf(" (expires %s)", s.Expires)
+	}
+	return text
+}
+
+// loadSidecarSuppressions reads the annotation file next to a policy file.
+// It returns no suppressions if there is none.
+func loadSidecarSuppressions(jsonFile string) ([]Suppression, error) {
+	for _, suffix := range sidecarSuffixes {
+		fileData, err := os.ReadFile(jsonFile + suffix)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var file suppressionFile
+		if err := decodeStrict(fileData, &file); err != nil {
+			return nil, fmt.Errorf("invalid suppression file '%s': %s", jsonFile+suffix, err)
+		}
+		for _, s := range file.Suppressions {
+			if err := s.validate(); err != nil {
+				return nil, fmt.Errorf("invalid suppression file '%s': %s", jsonFile+suffix, err)
+			}
+			if len(s.Paths) > 0 {
+				return nil, fmt.Errorf("invalid suppression file '%s': paths are not allowed", jsonFile+suffix)
+			}
+		}
+		return file.Suppressions, nil
+	}
+	return nil, nil
+}
+
+// suppress marks the findings matched by an unexpired suppression.
+func (v *Verifier) suppress(findings []Finding) []Finding {
+	for i, finding := range findings {
+		for _, s := range v.suppressions {
+			if s.matches(finding) && !s.expired(v.today()) {
+				s := s
+				findings[i].Suppression = &s
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// ExpiredSuppressions returns the suppressions that are past their expiry
+// date. They no longer silence anything and fail the run.
+func (v *Verifier) ExpiredSuppressions() []Suppression {
+	var expired []Suppression
+	for _, s := range v.suppressions {
+		if s.expired(v.today()) {
+			expired = append(expired, s)
+		}
+	}
+	return expired
+}
+
+func (v *Verifier) today() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,150 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const describePolicyJSON = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {"Sid": "Describe", "Effect": "Allow", "Action": "ec2:DescribeInstances", "Resource": "*"},
+    {"Sid": "Terminate", "Effect": "Allow", "Action": "ec2:TerminateInstances", "Resource": "*"}
+  ]
+}`
+
+func TestSuppress(t *testing.T) {
+	v := newVerifier()
+	v.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+	v.suppressions = []Suppression{
+		{Rule: "wildcard-resource", Sid: "Describe", Reason: "no resource-level permissions", Expires: "2025-06-01"},
+		{Rule: "wildcard-resource", Sid: "Terminate", Reason: "temporary", Expires: "2025-05-31"},
+	}
+
+	input, err := parseInput([]byte(describePolicyJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := v.verifyPolicyDocument(input.(map[string]interface{}), InlinePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, but got %v", findings)
+	}
+	if findings[0].Suppression == nil || findings[0].Suppression.Reason != "no resource-level permissions" {
+		t.Errorf("Expected finding on Describe to be suppressed, but got %v", findings[0].Suppression)
+	}
+	if findings[1].Suppression != nil {
+		t.Errorf("Expected expired suppression not to apply to Terminate")
+	}
+	if passed(findings) {
+		t.Errorf("Expected unsuppressed finding to fail verification")
+	}
+
+	expired := v.ExpiredSuppressions()
+	if len(expired) != 1 || expired[0].Sid != "Terminate" {
+		t.Errorf("Expected Terminate suppression to be expired, but got %v", expired)
+	}
+}
+
+func TestReadJSONsFromFileSidecarSuppressions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		sidecar        string
+		expectedResult bool
+		expectedError  bool
+	}{
+		{
+			name: "AllSuppressed",
+			sidecar: `suppressions:
+  - {rule: wildcard-resource, sid: Describe, reason: no resource-level permissions}
+  - {rule: wildcard-resource, sid: Terminate, reason: break-glass role, expires: "2999-01-01"}
+`,
+			expectedResult: true,
+		},
+		{
+			name: "ExpiredSuppression",
+			sidecar: `suppressions:
+  - {rule: wildcard-resource, sid: Describe, reason: no resource-level permissions}
+  - {rule: wildcard-resource, sid: Terminate, reason: break-glass role, expires: "2000-01-01"}
+`,
+			expectedResult: false,
+		},
+		{
+			name: "ExpiredUnusedSuppression",
+			sidecar: `suppressions:
+  - {rule: wildcard-resource, sid: Describe, reason: no resource-level permissions}
+  - {rule: wildcard-resource, sid: Terminate, reason: break-glass role}
+  - {rule: wildcard-resource, sid: Removed, reason: old statement, expires: "2000-01-01"}
+`,
+			expectedResult: false,
+		},
+		{
+			name:          "MissingReason",
+			sidecar:       "suppressions: [{rule: wildcard-resource, sid: Describe}]",
+			expectedError: true,
+		},
+		{
+			name:          "InvalidExpiry",
+			sidecar:       "suppressions: [{rule: wildcard-resource, sid: Describe, reason: x, expires: tomorrow}]",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			if err := os.WriteFile(path, []byte(describePolicyJSON), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path+".suppressions.yaml", []byte(tc.sidecar), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := filePassed(newVerifier(), path)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected non-nil error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res != tc.expectedResult {
+				t.Errorf("Expected result '%t', but got %t", tc.expectedResult, res)
+			}
+		})
+	}
+}
+
+func TestConfigSuppressions(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, `
+suppressions:
+  - rule: wildcard-resource
+    sid: Describe
+    paths: ["ec2/**"]
+    reason: no resource-level permissions
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]int{"ec2/role/policy.json": 1, "s3/policy.json": 0} {
+		v, err := cfg.Verifier(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v.suppressions) != expected {
+			t.Errorf("%s: expected %d suppressions, but got %d", path, expected, len(v.suppressions))
+		}
+	}
+
+	if _, err := LoadConfig(writeConfig(t, "suppressions: [{rule: wildcard-resource, sid: Describe}]"), ""); err == nil {
+		t.Errorf("Expected non-nil error for suppression without reason, got nil")
+	}
+}
@@ -0,0 +1,173 @@
+package iampolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type terraformPlan struct {
+	FormatVersion string `json:"format_version"`
+	PlannedValues struct {
+		RootModule terraformModule `json:"root_module"`
+	} `json:"planned_values"`
+	PriorState struct {
+		Values struct {
+			RootModule terraformModule `json:"root_module"`
+		} `json:"values"`
+	} `json:"prior_state"`
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			AfterUnknown map[string]interface{} `json:"after_unknown"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+type terraformModule struct {
+	Resources    []terraformResource `json:"resources"`
+	ChildModules []terraformModule   `json:"child_modules"`
+}
+
+type terraformResource struct {
+	Address string                 `json:"address"`
+	Mode    string                 `json:"mode"`
+	Type    string                 `json:"type"`
+	Values  map[string]interface{} `json:"values"`
+}
+
+// PolicyResult is the verification result of one policy document taken from
+// an input holding several of them. Source names the document in the report.
+type PolicyResult struct {
+	Source   string    `json:"source"`
+	Result   bool      `json:"passed"`
+	Findings []Finding `json:"findings,omitempty"`
+	Err      error     `json:"-"`
+}
+
+func isTerraformPlan(data map[string]interface{}) bool {
+	_, hasFormat := data["format_version"]
+	_, hasPlanned := data["planned_values"]
+	_, hasChanges := data["resource_changes"]
+	return hasFormat && (hasPlanned || hasChanges)
+}
+
+func (m terraformModule) allResources() []terraformResource {
+	resources := append([]terraformResource{}, m.Resources...)
+	for _, child := range m.ChildModules {
+		resources = append(resources, child.allResources()...)
+	}
+	return resources
+}
+
+// verifyTerraformPlan verifies every IAM policy found in `terraform show -json`
+// output. Managed resources come from planned_values; aws_iam_policy_document
+// data sources are usually read at plan time and therefore live in prior_state.
+func (v *Verifier) verifyTerraformPlan(fileData []byte) ([]PolicyResult, error) {
+	var plan terraformPlan
+	if err := json.Unmarshal(fileData, &plan); err != nil {
+		return nil, err
+	}
+
+	unknown := map[string]map[string]interface{}{}
+	for _, change := range plan.ResourceChanges {
+		unknown[change.Address] = change.Change.AfterUnknown
+	}
+
+	var results []PolicyResult
+	seen := map[string]bool{}
+	resources := append(plan.PlannedValues.RootModule.allResources(), plan.PriorState.Values.RootModule.allResources()...)
+	for _, resource := range resources {
+		if seen[resource.Address] {
+			continue
+		}
+		seen[resource.Address] = true
+		results = append(results, v.verifyTerraformResource(resource, unknown[resource.Address])...)
+	}
+
+	if len(results) == 0 {
+		return nil, errors.New("no IAM policies found in Terraform plan")
+	}
+	return results, nil
+}
+
+// verifyTerraformResource verifies the policies of a resource. unknown is
+// the after_unknown object of the resource's change, marking the values
+// only known after apply.
+func (v *Verifier) verifyTerraformResource(resource terraformResource, unknown map[string]interface{}) []PolicyResult {
+	var results []PolicyResult
+	verify := func(source string, value interface{}, unknown bool, kind PolicyKind) {
+		if _, err := decodeTerraformPolicy(value, unknown); err != nil {
+			results = append(results, PolicyResult{Source: source, Err: err})
+			return
+		}
+		results = append(results, v.verifyDocument(source, value, kind))
+	}
+
+	switch {
+	case resource.Mode == "data" && resource.Type == "aws_iam_policy_document":
+		value := resource.Values["json"]
+		kind := ManagedPolicy
+		if document, err := decodeTerraformPolicy(value, false); err == nil && hasPrincipal(document) {
+			kind = TrustPolicy
+		}
+		verify(resource.Address, value, unknown["json"] == true, kind)
+	case resource.Mode != "managed":
+	case resource.Type == "aws_iam_role_policy":
+		verify(resource.Address, resource.Values["policy"], unknown["policy"] == true, InlinePolicy)
+	case resource.Type == "aws_iam_policy":
+		verify(resource.Address, resource.Values["policy"], unknown["policy"] == true, ManagedPolicy)
+	case resource.Type == "aws_iam_role":
+		if value, ok := resource.Values["assume_role_policy"]; ok || unknown["assume_role_policy"] == true {
+			verify(resource.Address+".assume_role_policy", value, unknown["assume_role_policy"] == true, TrustPolicy)
+		}
+		inlinePolicies, _ := resource.Values["inline_policy"].([]interface{})
+		unknownInline, _ := unknown["inline_policy"].([]interface{})
+		for i, item := range inlinePolicies {
+			inline, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// An empty inline_policy block is how Terraform expresses
+			// "remove all inline policies", and the AWS provider writes one
+			// for roles without inline policies, so there is nothing to
+			// verify.
+			name, _ := inline["name"].(string)
+			if name == "" && (inline["policy"] == nil || inline["policy"] == "") {
+				continue
+			}
+			policyUnknown := unknown["inline_policy"] == true
+			if i < len(unknownInline) {
+				itemUnknown, _ := unknownInline[i].(map[string]interface{})
+				policyUnknown = policyUnknown || itemUnknown["policy"] == true
+			}
+			verify(fmt.Sprintf("%s.inline_policy[%q]", resource.Address, name), inline["policy"], policyUnknown, InlinePolicy)
+		}
+	}
+
+	return results
+}
+
+// decodeTerraformPolicy decodes a policy attribute. Values Terraform only
+// knows after apply are missing from the plan or marked unknown.
+func decodeTerraformPolicy(value interface{}, unknown bool) (map[string]interface{}, error) {
+	if unknown || value == nil {
+		return nil, errors.New("policy is not known until apply")
+	}
+	if value == "" {
+		return nil, errors.New("policy is empty")
+	}
+	return decodePolicyDocument(value)
+}
+
+func hasPrincipal(document map[string]interface{}) bool {
+	statements, _ := document["Statement"].([]interface{})
+	for _, statement := range statements {
+		if statementMap, ok := statement.(map[string]interface{}); ok {
+			if _, ok := statementMap["Principal"]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
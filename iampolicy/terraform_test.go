@@ -0,0 +1,204 @@
+package iampolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const terraformPlanJSON = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_iam_role.app",
+          "mode": "managed",
+          "type": "aws_iam_role",
+          "values": {
+            "name": "app",
+            "assume_role_policy": "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"sts:AssumeRole\",\"Principal\":{\"Service\":\"ec2.amazonaws.com\"}}]}",
+            "inline_policy": [
+              {
+                "name": "logs",
+                "policy": "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"logs:PutLogEvents\",\"Resource\":\"*\"}]}"
+              }
+            ]
+          }
+        },
+        {
+          "address": "aws_iam_role_policy.read",
+          "mode": "managed",
+          "type": "aws_iam_role_policy",
+          "values": {
+            "name": "read",
+            "policy": "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":[\"s3:GetObject\"],\"Resource\":\"arn:aws:s3:::bucket/*\"}]}"
+          }
+        },
+        {
+          "address": "aws_s3_bucket.data",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "values": {"bucket": "data"}
+        }
+      ],
+      "child_modules": [
+        {
+          "resources": [
+            {
+              "address": "module.ci.aws_iam_policy.deploy",
+              "mode": "managed",
+              "type": "aws_iam_policy",
+              "values": {"name": "deploy"}
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "prior_state": {
+    "values": {
+      "root_module": {
+        "resources": [
+          {
+            "address": "data.aws_iam_policy_document.trust",
+            "mode": "data",
+            "type": "aws_iam_policy_document",
+            "values": {
+              "json": "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"sts:AssumeRole\",\"Principal\":{\"AWS\":\"*\"}}]}"
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+func TestVerifyTerraformPlan(t *testing.T) {
+	results, err := newVerifier().verifyTerraformPlan([]byte(terraformPlanJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		source         string
+		expectedResult bool
+		expectedError  string
+	}{
+		{"aws_iam_role.app.assume_role_policy", true, ""},
+		{`aws_iam_role.app.inline_policy["logs"]`, false, ""},
+		{"aws_iam_role_policy.read", true, ""},
+		{"module.ci.aws_iam_policy.deploy", false, "policy is not known until apply"},
+		{"data.aws_iam_policy_document.trust", false, ""},
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, but got %d: %v", len(expected), len(results), results)
+	}
+	for i, tc := range expected {
+		res := results[i]
+		if res.Source != tc.source {
+			t.Errorf("Expected source '%s', but got '%s'", tc.source, res.Source)
+		}
+		if res.Err != nil {
+			if tc.expectedError != res.Err.Error() {
+				t.Errorf("%s: expected error '%s', but got %v", tc.source, tc.expectedError, res.Err)
+			}
+		} else if tc.expectedResult != res.Result {
+			t.Errorf("%s: expected result '%t', but got %t", tc.source, tc.expectedResult, res.Result)
+		}
+	}
+}
+
+// terraformUnknownPlanJSON holds a role without inline policies, as the AWS
+// provider plans it, and policies only known after apply.
+const terraformUnknownPlanJSON = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_iam_role.a",
+          "mode": "managed",
+          "type": "aws_iam_role",
+          "values": {
+            "name": "a",
+            "assume_role_policy": "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"sts:AssumeRole\",\"Principal\":{\"Service\":\"ec2.amazonaws.com\"}}]}",
+            "inline_policy": [{"name": "", "policy": ""}]
+          }
+        },
+        {
+          "address": "aws_iam_role_policy.computed",
+          "mode": "managed",
+          "type": "aws_iam_role_policy",
+          "values": {"name": "computed", "policy": ""}
+        },
+        {
+          "address": "aws_iam_role_policy.empty",
+          "mode": "managed",
+          "type": "aws_iam_role_policy",
+          "values": {"name": "empty", "policy": ""}
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {"address": "aws_iam_role.a", "change": {"after_unknown": {"inline_policy": [{}]}}},
+    {"address": "aws_iam_role_policy.computed", "change": {"after_unknown": {"policy": true}}},
+    {"address": "aws_iam_role_policy.empty", "change": {"after_unknown": {}}}
+  ]
+}`
+
+func TestVerifyTerraformPlanUnknownPolicies(t *testing.T) {
+	results, err := newVerifier().verifyTerraformPlan([]byte(terraformUnknownPlanJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		source        string
+		expectedError string
+	}{
+		{"aws_iam_role.a.assume_role_policy", ""},
+		{"aws_iam_role_policy.computed", "policy is not known until apply"},
+		{"aws_iam_role_policy.empty", "policy is empty"},
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, but got %d: %v", len(expected), len(results), results)
+	}
+	for i, tc := range expected {
+		res := results[i]
+		if res.Source != tc.source {
+			t.Errorf("Expected source '%s', but got '%s'", tc.source, res.Source)
+		}
+		if tc.expectedError == "" && (res.Err != nil || !res.Result) {
+			t.Errorf("%s: expected policy to pass, but got %t, %v", tc.source, res.Result, res.Err)
+		}
+		if tc.expectedError != "" && (res.Err == nil || res.Err.Error() != tc.expectedError) {
+			t.Errorf("%s: expected error '%s', but got %v", tc.source, tc.expectedError, res.Err)
+		}
+	}
+}
+
+func TestVerifyTerraformPlanWithoutPolicies(t *testing.T) {
+	_, err := newVerifier().verifyTerraformPlan([]byte(`{"format_version": "1.2", "planned_values": {"root_module": {}}}`))
+	if err == nil {
+		t.Errorf("Expected non-nil error for plan without IAM policies, got nil")
+	}
+}
+
+func TestReadJSONsFromFileTerraformPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, []byte(terraformPlanJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := filePassed(newVerifier(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Errorf("Expected result 'false' for plan with wildcard policies, but got true")
+	}
+}
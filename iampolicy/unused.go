@@ -0,0 +1,165 @@
+package iampolicy
+
+import (
+	"errors"
+	"strings"
+)
+
+// PermissionUsage tells how the calls in CloudTrail logs, made between the
+// First and Last event times, exercised the Allow statements of a policy.
+type PermissionUsage struct {
+	First      string
+	Last       string
+	Statements []StatementUsage
+}
+
+// CloudTrailUsage reads CloudTrail logs, .json or .json.gz files or
+// directories of them, and matches the calls the role made, or every
+// caller when role is empty, against the Allow statements of the policy.
+func CloudTrailUsage(policy *Policy, paths []string, role string) (*PermissionUsage, error) {
+	records, err := readCloudTrail(paths)
+	if err != nil {
+		return nil, err
+	}
+	first, last := logWindow(records, role)
+	if first == "" {
+		return nil, errors.New("no events found in the logs")
+	}
+	return &PermissionUsage{First: first, Last: last, Statements: permissionUsage(policy, records, role)}, nil
+}
+
+// StatementUsage records how the calls in a CloudTrail log window exercised
+// an Allow statement: how many calls it allowed, which actions each Action
+// entry was used for and which Resource entries were used.
+type StatementUsage struct {
+	Statement Statement
+	Calls     int
+	Actions   map[string][]string
+	Resources map[string]bool
+}
+
+// UnusedActions returns the Action entries no call used.
+func (u StatementUsage) UnusedActions() []string {
+	var unused []string
+	for _, action := range u.Statement.Action {
+		if len(u.Actions[action]) == 0 {
+			unused = append(unused, action)
+		}
+	}
+	return unused
+}
+
+// UnusedResources returns the Resource entries no call used.
+func (u StatementUsage) UnusedResources() []string {
+	var unused []string
+	for _, resource := range u.Statement.Resource {
+		if !u.Resources[resource] {
+			unused = append(unused, resource)
+		}
+	}
+	return unused
+}
+
+// permissionUsage matches the calls a role made, or every caller when role
+// is empty, against the Allow statements of a policy. A call naming no
+// resource is taken to use every Resource entry of the statements allowing
+// its action in the call's service, as nothing shows which one it needed.
+func permissionUsage(policy *Policy, records []cloudTrailRecord, role string) []StatementUsage {
+	var usage []StatementUsage
+	for _, statement := range policy.Statements {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		usage = append(usage, StatementUsage{Statement: statement, Actions: map[string][]string{}, Resources: map[string]bool{}})
+	}
+
+	for _, record := range records {
+		if (role != "" && !record.madeBy(role)) || record.denied() || record.EventName == "" {
+			continue
+		}
+		action := record.action()
+		arns := record.resourceARNs()
+		for i := range usage {
+			u := &usage[i]
+			actions := matchingEntries(u.Statement.Action, action, actionMatch)
+			if u.Statement.Raw["NotAction"] != nil {
+				if len(matchingEntries(u.Statement.NotAction, action, actionMatch)) > 0 {
+					continue
+				}
+			} else if len(actions) == 0 {
+				continue
+			}
+
+			var resources []string
+			if len(arns) == 0 {
+				resources = serviceEntries(u.Statement.Resource, record.service())
+				if u.Statement.Raw["NotResource"] == nil && len(resources) == 0 {
+					continue
+				}
+			} else {
+				for _, arn := range arns {
+					resources = append(resources, matchingEntries(u.Statement.Resource, arn, globMatch)...)
+				}
+				if u.Statement.Raw["NotResource"] != nil {
+					excluded := false
+					for _, arn := range arns {
+						excluded = excluded || len(matchingEntries(u.Statement.NotResource, arn, globMatch)) > 0
+					}
+					if excluded {
+						continue
+					}
+				} else if len(resources) == 0 {
+					continue
+				}
+			}
+
+			u.Calls++
+			for _, entry := range actions {
+				u.Actions[entry] = sortedUnique(append(u.Actions[entry], action))
+			}
+			for _, entry := range resources {
+				u.Resources[entry] = true
+			}
+		}
+	}
+	return usage
+}
+
+func matchingEntries(entries []string, value string, match func(pattern, value string) bool) []string {
+	var matching []string
+	for _, entry := range entries {
+		if match(entry, value) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// serviceEntries returns the Resource entries that can name resources of a
+// service: "*" and ARNs whose service field matches it.
+func serviceEntries(entries []string, service string) []string {
+	var matching []string
+	for _, entry := range entries {
+		fields := strings.SplitN(entry, ":", 4)
+		if entry == "*" || (len(fields) == 4 && globMatch(fields[2], service)) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// logWindow returns the times of the first and last calls considered.
+func logWindow(records []cloudTrailRecord, role string) (first, last string) {
+	for _, record := range records {
+		if role != "" && !record.madeBy(role) {
+			continue
+		}
+		if first == "" || record.EventTime < first {
+			first = record.EventTime
+		}
+		if record.EventTime > last {
+			last = record.EventTime
+		}
+	}
+	return first, last
+}
@@ -0,0 +1,53 @@
+package iampolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPermissionUsage(t *testing.T) {
+	records, err := readCloudTrail([]string{writeCloudTrail(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := parsePolicy(map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{
+		map[string]interface{}{"Sid": "Objects", "Effect": "Allow", "Action": []interface{}{"s3:*Object", "s3:ListBucket"}, "Resource": []interface{}{"arn:aws:s3:::data/*", "arn:aws:s3:::archive/*"}},
+		map[string]interface{}{"Sid": "Functions", "Effect": "Allow", "Action": "lambda:Get*", "Resource": "arn:aws:lambda:*:123456789012:function:*"},
+		map[string]interface{}{"Sid": "Queues", "Effect": "Allow", "NotAction": "sqs:SendMessage", "Resource": "arn:aws:sqs:*:*:*"},
+		map[string]interface{}{"Sid": "NoDelete", "Effect": "Deny", "Action": "s3:DeleteObject", "Resource": "*"},
+	}}, InlinePolicy)
+
+	usage := permissionUsage(policy, records, "app")
+	if len(usage) != 3 {
+		t.Fatalf("Expected usage of 3 Allow statements, but got %d", len(usage))
+	}
+
+	objects := usage[0]
+	if objects.Calls != 2 {
+		t.Errorf("Objects: expected 2 calls, but got %d", objects.Calls)
+	}
+	if expected := []string{"s3:GetObject", "s3:PutObject"}; !reflect.DeepEqual(objects.Actions["s3:*Object"], expected) {
+		t.Errorf("Objects: expected s3:*Object used as %v, but got %v", expected, objects.Actions["s3:*Object"])
+	}
+	if expected := []string{"s3:ListBucket"}; !reflect.DeepEqual(objects.UnusedActions(), expected) {
+		t.Errorf("Objects: expected unused actions %v, but got %v", expected, objects.UnusedActions())
+	}
+	if expected := []string{"arn:aws:s3:::archive/*"}; !reflect.DeepEqual(objects.UnusedResources(), expected) {
+		t.Errorf("Objects: expected unused resources %v, but got %v", expected, objects.UnusedResources())
+	}
+
+	// GetFunction names no resource, so it uses every Resource entry.
+	functions := usage[1]
+	if functions.Calls != 1 || len(functions.UnusedActions()) != 0 || len(functions.UnusedResources()) != 0 {
+		t.Errorf("Functions: expected 1 call using everything, but got %+v", functions)
+	}
+
+	if queues := usage[2]; queues.Calls != 0 {
+		t.Errorf("Queues: expected no calls, but got %d", queues.Calls)
+	}
+
+	// Every caller counts without a role.
+	if usage := permissionUsage(policy, records, ""); !reflect.DeepEqual(usage[0].Actions["s3:ListBucket"], []string(nil)) || usage[0].Calls != 2 {
+		t.Errorf("Expected ListBuckets of another role not to use s3:ListBucket, got %+v", usage[0])
+	}
+}
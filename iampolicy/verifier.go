@@ -0,0 +1,161 @@
+package iampolicy
+
+import "time"
+
+// Verifier validates policy documents and runs its rules against the ones
+// that are well-formed. Verifiers are created with NewVerifier or from a
+// configuration file with LoadConfig.
+type Verifier struct {
+	rules []Rule
+	// versions lists the accepted Version values; nil accepts the two
+	// versions AWS knows about.
+	versions []string
+	// kind forces the kind of single policies instead of detecting it.
+	kind *PolicyKind
+	// ignoreUnknownFields accepts fields IAM does not define in policy
+	// documents and statements.
+	ignoreUnknownFields bool
+	// wildcard overrides the wildcard policy of the wildcard-resource rule.
+	wildcard *WildcardPolicy
+	// threshold is the lowest severity of findings failing verification;
+	// nil means SeverityError.
+	threshold *Severity
+	// suppressions silence findings on statements with a given Sid.
+	suppressions []Suppression
+	now          func() time.Time
+	// file is the input file being verified, baseline holds the findings
+	// to hide and record collects findings for a new baseline.
+	file      string
+	baseline  *Baseline
+	record    *Baseline
+	baselined int
+	// declared holds the declarative rules of the configuration.
+	declared []Rule
+}
+
+// newVerifier returns a verifier running every rule enabled by default.
+func newVerifier() *Verifier {
+	return &Verifier{rules: defaultRules()}
+}
+
+// NewVerifier returns a verifier running every rule enabled by default,
+// configured by the options.
+func NewVerifier(options ...Option) (*Verifier, error) {
+	v := newVerifier()
+	if err := v.Apply(options...); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Rules returns the rules the verifier runs.
+func (v *Verifier) Rules() []Rule {
+	return append([]Rule{}, v.rules...)
+}
+
+// Rule returns the verifier's rule with the given ID, falling back to the
+// registered one, or a declarative rule of its configuration, for rules it
+// does not run.
+func (v *Verifier) Rule(id string) (Rule, bool) {
+	for _, rule := range v.rules {
+		if rule.ID() == id {
+			return rule, true
+		}
+	}
+	return lookupRule(v.declared, id)
+}
+
+func (v *Verifier) verifyIAMRolePolicy(data map[string]interface{}) ([]Finding, error) {
+	policyDocument, err := v.validateIAMRolePolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	return v.verifyPolicyDocument(policyDocument, InlinePolicy)
+}
+
+func (v *Verifier) verifyPolicyDocument(policyDocument map[string]interface{}, kind PolicyKind) ([]Finding, error) {
+	ok, err := v.validatePolicyDocument(policyDocument, kind)
+	if !ok {
+		return nil, err
+	}
+	return v.checkPolicy(parsePolicy(policyDocument, kind)), nil
+}
+
+func (v *Verifier) checkPolicy(policy *Policy) []Finding {
+	var findings []Finding
+	for _, rule := range v.rules {
+		findings = append(findings, rule.Check(policy)...)
+	}
+	return v.applyBaseline(policy, v.suppress(findings))
+}
+
+// ForFile returns a copy of the verifier for one input file, adding the
+// suppressions from the file's sidecar annotation file.
+func (v *Verifier) ForFile(jsonFile string) (*Verifier, error) {
+	sidecar, err := loadSidecarSuppressions(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	fileVerifier := v.named(jsonFile)
+	fileVerifier.suppressions = append(append([]Suppression{}, v.suppressions...), sidecar...)
+	return fileVerifier, nil
+}
+
+// named returns a copy of the verifier recording baseline entries under the
+// given input name.
+func (v *Verifier) named(name string) *Verifier {
+	named := *v
+	named.file = name
+	named.baselined = 0
+	return &named
+}
+
+// withoutRule returns a copy of the verifier that does not run the rule with
+// the given ID.
+func (v *Verifier) withoutRule(id string) *Verifier {
+	copied := *v
+	copied.rules = nil
+	for _, rule := range v.rules {
+		if rule.ID() != id {
+			copied.rules = append(copied.rules, rule)
+		}
+	}
+	return &copied
+}
+
+// verifyDocument decodes and verifies a policy document embedded in a larger
+// input, recording the outcome under the given source name.
+func (v *Verifier) verifyDocument(source string, value interface{}, kind PolicyKind) PolicyResult {
+	result := PolicyResult{Source: source}
+	document, err := decodePolicyDocument(value)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Findings, result.Err = v.verifyPolicyDocument(document, kind)
+	result.Result = result.Err == nil && v.passed(result.Findings)
+	return result
+}
+
+// passed reports whether no unsuppressed finding reaches the verifier's
+// severity threshold.
+func (v *Verifier) passed(findings []Finding) bool {
+	if v.threshold == nil {
+		return passed(findings)
+	}
+	for _, finding := range findings {
+		if finding.Suppression == nil && finding.Severity >= *v.threshold {
+			return false
+		}
+	}
+	return true
+}
+
+func passed(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Suppression == nil && finding.Severity >= SeverityError {
+			return false
+		}
+	}
+	return true
+}
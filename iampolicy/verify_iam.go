@@ -0,0 +1,381 @@
+package iampolicy
+
+import (
+	"errors"
+	"strings"
+)
+
+func checkPolicyDocumentFields(data map[string]interface{}) (bool, error) {
+	requiredFields := map[string]bool{
+		"Version":   true,
+		"Statement": true,
+	}
+
+	for field := range requiredFields {
+		if _, ok := data[field]; !ok {
+			return false, errors.New(field + " field is missing")
+		}
+	}
+
+	return true, nil
+}
+
+func checkStatementFields(data map[string]interface{}) (bool, error) {
+	if _, ok := data["Effect"]; !ok {
+		return false, errors.New("Effect field is missing")
+	}
+	for _, field := range []string{"Action", "Resource"} {
+		ok, err := checkExclusiveFields(data, field)
+		if !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// checkExclusiveFields requires exactly one of a field and its negated
+// form, e.g. Action or NotAction.
+func checkExclusiveFields(data map[string]interface{}, field string) (bool, error) {
+	_, ok := data[field]
+	_, notOk := data["Not"+field]
+	if ok && notOk {
+		return false, errors.New(field + " and Not" + field + " fields cannot be used together")
+	}
+	if !ok && !notOk {
+		return false, errors.New(field + " field is missing")
+	}
+
+	return true, nil
+}
+
+func checkActionField(data map[string]interface{}) (bool, error) {
+	field := "Action"
+	if _, ok := data[field]; !ok {
+		field = "NotAction"
+	}
+	action, _ := data[field]
+
+	switch act := action.(type) {
+	case string:
+		if act == "" {
+			return false, errors.New(field + " field is empty")
+		}
+	case []interface{}:
+		if len(act) == 0 {
+			return false, errors.New(field + " field is empty")
+		}
+		for _, a := range act {
+			if _, ok := a.(string); !ok {
+				return false, errors.New(field + " field contains non-string value")
+			}
+		}
+	default:
+		return false, errors.New(field + " field is not a string or a list")
+	}
+
+	return true, nil
+}
+
+func checkEffectField(data map[string]interface{}) (bool, error) {
+	effect, ok := data["Effect"].(string)
+	if !ok {
+		return false, errors.New("Effect field is not a string")
+	}
+
+	if effect != "Allow" && effect != "Deny" {
+		return false, errors.New("Effect field is not 'Allow' or 'Deny'")
+	}
+
+	return true, nil
+}
+
+func checkSidField(data map[string]interface{}) (bool, error) {
+	sid, ok := data["Sid"]
+	if !ok {
+		return true, nil
+	}
+
+	if _, ok := sid.(string); !ok {
+		return false, errors.New("Sid field is not a string")
+	}
+
+	return true, nil
+}
+
+func checkVersion(data map[string]interface{}) (bool, error) {
+	version, ok := data["Version"].(string)
+	if !ok {
+		return false, errors.New("Version field is not a string")
+	}
+
+	if version != "2012-10-17" && version != "2008-10-17" {
+		return false, errors.New("Version field is not '2012-10-17' neither '2008-10-17'")
+	}
+
+	return true, nil
+}
+
+func checkAllowedVersion(data map[string]interface{}, versions []string) (bool, error) {
+	version, ok := data["Version"].(string)
+	if !ok {
+		return false, errors.New("Version field is not a string")
+	}
+
+	for _, allowed := range versions {
+		if version == allowed {
+			return true, nil
+		}
+	}
+
+	return false, errors.New("Version field is not one of '" + strings.Join(versions, "', '") + "'")
+}
+
+// checkImproperFields rejects fields IAM does not define unless the verifier
+// ignores them.
+func (v *Verifier) checkImproperFields(data map[string]interface{}, requiredFields map[string]bool) (bool, error) {
+	if v.ignoreUnknownFields {
+		return true, nil
+	}
+	for key := range data {
+		if _, ok := requiredFields[key]; ok {
+			requiredFields[key] = true
+		} else {
+			return false, errors.New("unexpected field " + key)
+		}
+	}
+
+	return true, nil
+}
+
+func checkTrustStatementFields(data map[string]interface{}) (bool, error) {
+	requiredFields := map[string]bool{
+		"Effect":    true,
+		"Principal": true,
+	}
+
+	for field := range requiredFields {
+		if _, ok := data[field]; !ok {
+			return false, errors.New(field + " field is missing")
+		}
+	}
+
+	return checkExclusiveFields(data, "Action")
+}
+
+func checkResourceField(data map[string]interface{}) (bool, error) {
+	field := "Resource"
+	if _, ok := data[field]; !ok {
+		field = "NotResource"
+	}
+	resource, _ := data[field]
+
+	switch res := resource.(type) {
+	case string:
+	case []interface{}:
+		for _, res := range res {
+			if _, ok := res.(string); !ok {
+				return false, errors.New(field + " list contains non-string value")
+			}
+		}
+	default:
+		return false, errors.New(field + " field is not a string or a list")
+	}
+
+	return true, nil
+}
+
+// checkPrincipalField accepts "*" or a dictionary such as
+// {"Service": "ec2.amazonaws.com"} whose values are strings or lists.
+func checkPrincipalField(data map[string]interface{}) (bool, error) {
+	principal, _ := data["Principal"]
+
+	switch p := principal.(type) {
+	case string:
+		if p != "*" {
+			return false, errors.New("Principal field is not '*' or a dictionary")
+		}
+	case map[string]interface{}:
+		for _, value := range p {
+			switch v := value.(type) {
+			case string:
+			case []interface{}:
+				for _, item := range v {
+					if _, ok := item.(string); !ok {
+						return false, errors.New("Principal list contains non-string value")
+					}
+				}
+			default:
+				return false, errors.New("Principal value is not a string or a list")
+			}
+		}
+	default:
+		return false, errors.New("Principal field is not '*' or a dictionary")
+	}
+
+	return true, nil
+}
+
+type PolicyKind int
+
+const (
+	InlinePolicy PolicyKind = iota
+	ManagedPolicy
+	TrustPolicy
+)
+
+func (k PolicyKind) String() string {
+	switch k {
+	case ManagedPolicy:
+		return "managed"
+	case TrustPolicy:
+		return "trust"
+	default:
+		return "inline"
+	}
+}
+
+func verifyIAMRolePolicy(data map[string]interface{}) (bool, error) {
+	findings, err := newVerifier().verifyIAMRolePolicy(data)
+	if err != nil {
+		return false, err
+	}
+	return passed(findings), nil
+}
+
+func verifyPolicyDocument(policyDocument map[string]interface{}, kind PolicyKind) (bool, error) {
+	findings, err := newVerifier().verifyPolicyDocument(policyDocument, kind)
+	if err != nil {
+		return false, err
+	}
+	return passed(findings), nil
+}
+
+// validateIAMRolePolicy checks the {PolicyName, PolicyDocument} wrapper and
+// returns the policy document inside it.
+func (v *Verifier) validateIAMRolePolicy(data map[string]interface{}) (map[string]interface{}, error) {
+	requiredFields := map[string]bool{
+		"PolicyName":     false,
+		"PolicyDocument": false,
+	}
+	ok, err := v.checkImproperFields(data, requiredFields)
+	if !ok {
+		return nil, err
+	}
+	policyDocument, ok := data["PolicyDocument"].(map[string]interface{})
+	if !ok && data["PolicyDocument"] != nil {
+		return nil, errors.New("PolicyDocument is not a dictionary")
+	}
+
+	return policyDocument, nil
+}
+
+// validatePolicyDocument checks the structure of a bare policy document.
+// Permission policies must not use Principal; trust policies must name a
+// Principal and must not use Resource.
+func (v *Verifier) validatePolicyDocument(policyDocument map[string]interface{}, kind PolicyKind) (bool, error) {
+	requiredFields := map[string]bool{
+		"Version":   false,
+		"Statement": false,
+	}
+	ok, err := v.checkImproperFields(policyDocument, requiredFields)
+	if !ok {
+		return false, err
+	}
+
+	ok, err = checkPolicyDocumentFields(policyDocument)
+	if !ok {
+		return false, err
+	}
+	if v.versions == nil {
+		ok, err = checkVersion(policyDocument)
+	} else {
+		ok, err = checkAllowedVersion(policyDocument, v.versions)
+	}
+	if !ok {
+		return false, err
+	}
+
+	statements, ok := policyDocument["Statement"].([]interface{})
+	if !ok {
+		return false, errors.New("Statement field is not a list")
+	}
+
+	if len(statements) == 0 {
+		return false, errors.New("Statement field is empty")
+	}
+
+	for _, statement := range statements {
+		statementMap, ok := statement.(map[string]interface{})
+		if !ok {
+			return false, errors.New("Statement is not a dictionary")
+		}
+		requiredFields := map[string]bool{
+			"Sid":         false,
+			"Effect":      false,
+			"Principal":   false,
+			"Action":      false,
+			"NotAction":   false,
+			"Resource":    false,
+			"NotResource": false,
+			"Condition":   false,
+		}
+		ok, err = v.checkImproperFields(statementMap, requiredFields)
+		if !ok {
+			return false, err
+		}
+		if kind == TrustPolicy {
+			ok, err = checkTrustStatementFields(statementMap)
+		} else {
+			ok, err = checkStatementFields(statementMap)
+		}
+		if !ok {
+			return false, err
+		}
+		ok, err = checkActionField(statementMap)
+		if !ok {
+			return false, err
+		}
+		ok, err = checkEffectField(statementMap)
+		if !ok {
+			return false, err
+		}
+		ok, err = checkSidField(statementMap)
+		if !ok {
+			return false, err
+		}
+
+		if kind == TrustPolicy {
+			for _, field := range []string{"Resource", "NotResource"} {
+				if _, ok = statementMap[field]; ok {
+					return false, errors.New(field + " field is not allowed")
+				}
+			}
+			ok, err = checkPrincipalField(statementMap)
+			if !ok {
+				return false, err
+			}
+			continue
+		}
+
+		_, ok = statementMap["Principal"]
+		if ok {
+			return false, errors.New("Principal field is not allowed")
+		}
+		ok, err = checkResourceField(statementMap)
+		if !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// ruleIDs returns the IDs of the rules.
+func ruleIDs(rules []Rule) []string {
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID())
+	}
+	return ids
+}
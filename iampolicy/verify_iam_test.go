@@ -1,4 +1,4 @@
-package main
+package iampolicy
 
 import (
 	"fmt"
@@ -513,6 +513,99 @@ func TestVerifyIAMRolePolicy(t *testing.T) {
 			expectedResult: false,
 			expectedError:  "Action field contains non-string value",
 		},
+		{
+			name: "ActionFieldIsString",
+			data: map[string]interface{}{
+				"PolicyName": "root",
+				"PolicyDocument": map[string]interface{}{
+					"Version": "2012-10-17",
+					"Statement": []interface{}{
+						map[string]interface{}{
+							"Effect":   "Allow",
+							"Action":   "s3:ListAllMyBuckets",
+							"Sid":      "S3ListAccess",
+							"Resource": "not*",
+						},
+					},
+				},
+			},
+			expectedResult: true,
+			expectedError:  "",
+		},
+		{
+			name: "NotActionAndNotResource",
+			data: map[string]interface{}{
+				"PolicyName": "root",
+				"PolicyDocument": map[string]interface{}{
+					"Version": "2012-10-17",
+					"Statement": []interface{}{
+						map[string]interface{}{
+							"Effect":      "Deny",
+							"NotAction":   []interface{}{"iam:*"},
+							"NotResource": "arn:aws:s3:::bucket/*",
+						},
+					},
+				},
+			},
+			expectedResult: true,
+			expectedError:  "",
+		},
+		{
+			name: "ActionAndNotActionTogether",
+			data: map[string]interface{}{
+				"PolicyName": "root",
+				"PolicyDocument": map[string]interface{}{
+					"Version": "2012-10-17",
+					"Statement": []interface{}{
+						map[string]interface{}{
+							"Effect":    "Allow",
+							"Action":    "s3:GetObject",
+							"NotAction": "s3:PutObject",
+							"Resource":  "not*",
+						},
+					},
+				},
+			},
+			expectedResult: false,
+			expectedError:  "Action and NotAction fields cannot be used together",
+		},
+		{
+			name: "NotActionFieldIsEmpty",
+			data: map[string]interface{}{
+				"PolicyName": "root",
+				"PolicyDocument": map[string]interface{}{
+					"Version": "2012-10-17",
+					"Statement": []interface{}{
+						map[string]interface{}{
+							"Effect":    "Allow",
+							"NotAction": []interface{}{},
+							"Resource":  "not*",
+						},
+					},
+				},
+			},
+			expectedResult: false,
+			expectedError:  "NotAction field is empty",
+		},
+		{
+			name: "SidFieldIsNotString",
+			data: map[string]interface{}{
+				"PolicyName": "root",
+				"PolicyDocument": map[string]interface{}{
+					"Version": "2012-10-17",
+					"Statement": []interface{}{
+						map[string]interface{}{
+							"Effect":   "Allow",
+							"Action":   []interface{}{"iam:ListRoles"},
+							"Sid":      1,
+							"Resource": "not*",
+						},
+					},
+				},
+			},
+			expectedResult: false,
+			expectedError:  "Sid field is not a string",
+		},
 		{
 			name: "EffectFieldIsNotString",
 			data: map[string]interface{}{
@@ -688,7 +781,17 @@ func TestVerifyIAMRolePolicy(t *testing.T) {
 	}
 
 }
-func TestReadJSONsFromFile(t *testing.T) {
+
+// filePassed verifies a file and returns whether it passed.
+func filePassed(v *Verifier, jsonFile string) (bool, error) {
+	report, err := v.VerifyFile(jsonFile)
+	if err != nil {
+		return false, err
+	}
+	return report.Passed, nil
+}
+
+func TestVerifyFileInvalidJSON(t *testing.T) {
 	tmpfile, err := ioutil.TempFile("", "test.json")
 	if err != nil {
 		t.Fatal(err)
@@ -706,7 +809,7 @@ func TestReadJSONsFromFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = readJSONsFromFile(tmpfile.Name())
+	_, err = newVerifier().VerifyFile(tmpfile.Name())
 	if err == nil {
 		t.Errorf("Expected non-nil error for invalid JSON, got nil")
 	}
@@ -0,0 +1,615 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: iamverify/v1/verify.proto
+
+package iamverifypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The policy as JSON text, in any single-policy input format of the
+	// command line, or URL-encoded as returned by the IAM API.
+	Policy string `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	// Returned in the response.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type VerifyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Whether the policy is valid and has no unsuppressed error finding.
+	Result bool `protobuf:"varint,2,opt,name=result,proto3" json:"result,omitempty"`
+	// "inline", "managed" or "trust".
+	Kind     string     `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Findings []*Finding `protobuf:"bytes,4,rep,name=findings,proto3" json:"findings,omitempty"`
+	// Why the input is not a valid policy; there are no findings then.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetResult() bool {
+	if x != nil {
+		return x.Result
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetFindings() []*Finding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+func (x *VerifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Finding struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rule  string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// "info", "warning" or "error".
+	Severity string `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Message  string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Index of the statement, or -1 for findings about the whole policy.
+	Statement int32  `protobuf:"varint,4,opt,name=statement,proto3" json:"statement,omitempty"`
+	Sid       string `protobuf:"bytes,5,opt,name=sid,proto3" json:"sid,omitempty"`
+	// Set when a suppression silenced the finding.
+	Suppression   *Suppression `protobuf:"bytes,6,opt,name=suppression,proto3" json:"suppression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Finding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Finding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Finding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Finding) GetStatement() int32 {
+	if x != nil {
+		return x.Statement
+	}
+	return 0
+}
+
+func (x *Finding) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *Finding) GetSuppression() *Suppression {
+	if x != nil {
+		return x.Suppression
+	}
+	return nil
+}
+
+type Suppression struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Sid           string                 `protobuf:"bytes,2,opt,name=sid,proto3" json:"sid,omitempty"`
+	Paths         []string               `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Expires       string                 `protobuf:"bytes,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Suppression) Reset() {
+	*x = Suppression{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suppression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suppression) ProtoMessage() {}
+
+func (x *Suppression) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suppression.ProtoReflect.Descriptor instead.
+func (*Suppression) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Suppression) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Suppression) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *Suppression) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *Suppression) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Suppression) GetExpires() string {
+	if x != nil {
+		return x.Expires
+	}
+	return ""
+}
+
+type EvaluateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The policy as JSON text, as in VerifyRequest.
+	Policy        string         `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Request       *AccessRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EvaluateRequest) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetRequest() *AccessRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type AccessRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "Type:value", e.g. "Service:ec2.amazonaws.com"; used for trust policies.
+	Principal string `protobuf:"bytes,1,opt,name=principal,proto3" json:"principal,omitempty"`
+	Action    string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// Used for permission policies.
+	Resource string `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Values of condition keys, single values or lists.
+	Context       *structpb.Struct `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRequest) Reset() {
+	*x = AccessRequest{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequest) ProtoMessage() {}
+
+func (x *AccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequest.ProtoReflect.Descriptor instead.
+func (*AccessRequest) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AccessRequest) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetResource() string {
+	if x != nil {
+		return x.Resource
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetContext() *structpb.Struct {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type EvaluateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "allowed", "explicitDeny" or "implicitDeny".
+	Decision      string   `protobuf:"bytes,1,opt,name=decision,proto3" json:"decision,omitempty"`
+	AllowedBy     []string `protobuf:"bytes,2,rep,name=allowed_by,json=allowedBy,proto3" json:"allowed_by,omitempty"`
+	DeniedBy      []string `protobuf:"bytes,3,rep,name=denied_by,json=deniedBy,proto3" json:"denied_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	mi := &file_iamverify_v1_verify_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_iamverify_v1_verify_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_iamverify_v1_verify_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EvaluateResponse) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *EvaluateResponse) GetAllowedBy() []string {
+	if x != nil {
+		return x.AllowedBy
+	}
+	return nil
+}
+
+func (x *EvaluateResponse) GetDeniedBy() []string {
+	if x != nil {
+		return x.DeniedBy
+	}
+	return nil
+}
+
+var File_iamverify_v1_verify_proto protoreflect.FileDescriptor
+
+const file_iamverify_v1_verify_proto_rawDesc = "" +
+	"\n" +
+	"\x19iamverify/v1/verify.proto\x12\fiamverify.v1\x1a\x1cgoogle/protobuf/struct.proto\"7\n" +
+	"\rVerifyRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\x95\x01\n" +
+	"\x0eVerifyResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06result\x18\x02 \x01(\bR\x06result\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x121\n" +
+	"\bfindings\x18\x04 \x03(\v2\x15.iamverify.v1.FindingR\bfindings\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\xc0\x01\n" +
+	"\aFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x1a\n" +
+	"\bseverity\x18\x02 \x01(\tR\bseverity\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1c\n" +
+	"\tstatement\x18\x04 \x01(\x05R\tstatement\x12\x10\n" +
+	"\x03sid\x18\x05 \x01(\tR\x03sid\x12;\n" +
+	"\vsuppression\x18\x06 \x01(\v2\x19.iamverify.v1.SuppressionR\vsuppression\"{\n" +
+	"\vSuppression\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x10\n" +
+	"\x03sid\x18\x02 \x01(\tR\x03sid\x12\x14\n" +
+	"\x05paths\x18\x03 \x03(\tR\x05paths\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x18\n" +
+	"\aexpires\x18\x05 \x01(\tR\aexpires\"`\n" +
+	"\x0fEvaluateRequest\x12\x16\n" +
+	"\x06policy\x18\x01 \x01(\tR\x06policy\x125\n" +
+	"\arequest\x18\x02 \x01(\v2\x1b.iamverify.v1.AccessRequestR\arequest\"\x94\x01\n" +
+	"\rAccessRequest\x12\x1c\n" +
+	"\tprincipal\x18\x01 \x01(\tR\tprincipal\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x1a\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x121\n" +
+	"\acontext\x18\x04 \x01(\v2\x17.google.protobuf.StructR\acontext\"j\n" +
+	"\x10EvaluateResponse\x12\x1a\n" +
+	"\bdecision\x18\x01 \x01(\tR\bdecision\x12\x1d\n" +
+	"\n" +
+	"allowed_by\x18\x02 \x03(\tR\tallowedBy\x12\x1b\n" +
+	"\tdenied_by\x18\x03 \x03(\tR\bdeniedBy2\xeb\x01\n" +
+	"\vIAMVerifier\x12C\n" +
+	"\x06Verify\x12\x1b.iamverify.v1.VerifyRequest\x1a\x1c.iamverify.v1.VerifyResponse\x12L\n" +
+	"\vVerifyBatch\x12\x1b.iamverify.v1.VerifyRequest\x1a\x1c.iamverify.v1.VerifyResponse(\x010\x01\x12I\n" +
+	"\bEvaluate\x12\x1d.iamverify.v1.EvaluateRequest\x1a\x1e.iamverify.v1.EvaluateResponseB\x15P\x01Z\x11test3/iamverifypbb\x06proto3"
+
+var (
+	file_iamverify_v1_verify_proto_rawDescOnce sync.Once
+	file_iamverify_v1_verify_proto_rawDescData []byte
+)
+
+func file_iamverify_v1_verify_proto_rawDescGZIP() []byte {
+	file_iamverify_v1_verify_proto_rawDescOnce.Do(func() {
+		file_iamverify_v1_verify_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_iamverify_v1_verify_proto_rawDesc), len(file_iamverify_v1_verify_proto_rawDesc)))
+	})
+	return file_iamverify_v1_verify_proto_rawDescData
+}
+
+var file_iamverify_v1_verify_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_iamverify_v1_verify_proto_goTypes = []any{
+	(*VerifyRequest)(nil),    // 0: iamverify.v1.VerifyRequest
+	(*VerifyResponse)(nil),   // 1: iamverify.v1.VerifyResponse
+	(*Finding)(nil),          // 2: iamverify.v1.Finding
+	(*Suppression)(nil),      // 3: iamverify.v1.Suppression
+	(*EvaluateRequest)(nil),  // 4: iamverify.v1.EvaluateRequest
+	(*AccessRequest)(nil),    // 5: iamverify.v1.AccessRequest
+	(*EvaluateResponse)(nil), // 6: iamverify.v1.EvaluateResponse
+	(*structpb.Struct)(nil),  // 7: google.protobuf.Struct
+}
+var file_iamverify_v1_verify_proto_depIdxs = []int32{
+	2, // 0: iamverify.v1.VerifyResponse.findings:type_name -> iamverify.v1.Finding
+	3, // 1: iamverify.v1.Finding.suppression:type_name -> iamverify.v1.Suppression
+	5, // 2: iamverify.v1.EvaluateRequest.request:type_name -> iamverify.v1.AccessRequest
+	7, // 3: iamverify.v1.AccessRequest.context:type_name -> google.protobuf.Struct
+	0, // 4: iamverify.v1.IAMVerifier.Verify:input_type -> iamverify.v1.VerifyRequest
+	0, // 5: iamverify.v1.IAMVerifier.VerifyBatch:input_type -> iamverify.v1.VerifyRequest
+	4, // 6: iamverify.v1.IAMVerifier.Evaluate:input_type -> iamverify.v1.EvaluateRequest
+	1, // 7: iamverify.v1.IAMVerifier.Verify:output_type -> iamverify.v1.VerifyResponse
+	1, // 8: iamverify.v1.IAMVerifier.VerifyBatch:output_type -> iamverify.v1.VerifyResponse
+	6, // 9: iamverify.v1.IAMVerifier.Evaluate:output_type -> iamverify.v1.EvaluateResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_iamverify_v1_verify_proto_init() }
+func file_iamverify_v1_verify_proto_init() {
+	if File_iamverify_v1_verify_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_iamverify_v1_verify_proto_rawDesc), len(file_iamverify_v1_verify_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_iamverify_v1_verify_proto_goTypes,
+		DependencyIndexes: file_iamverify_v1_verify_proto_depIdxs,
+		MessageInfos:      file_iamverify_v1_verify_proto_msgTypes,
+	}.Build()
+	File_iamverify_v1_verify_proto = out.File
+	file_iamverify_v1_verify_proto_goTypes = nil
+	file_iamverify_v1_verify_proto_depIdxs = nil
+}
@@ -0,0 +1,206 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: iamverify/v1/verify.proto
+
+package iamverifypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IAMVerifier_Verify_FullMethodName      = "/iamverify.v1.IAMVerifier/Verify"
+	IAMVerifier_VerifyBatch_FullMethodName = "/iamverify.v1.IAMVerifier/VerifyBatch"
+	IAMVerifier_Evaluate_FullMethodName    = "/iamverify.v1.IAMVerifier/Evaluate"
+)
+
+// IAMVerifierClient is the client API for IAMVerifier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IAMVerifier verifies IAM policies and simulates requests against them.
+// Its messages mirror the JSON answers of the HTTP server.
+type IAMVerifierClient interface {
+	// Verify verifies a single policy.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// VerifyBatch verifies a stream of policies, answering each request in
+	// order; responses carry the id of their request.
+	VerifyBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[VerifyRequest, VerifyResponse], error)
+	// Evaluate simulates a request against a policy.
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+}
+
+type iAMVerifierClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIAMVerifierClient(cc grpc.ClientConnInterface) IAMVerifierClient {
+	return &iAMVerifierClient{cc}
+}
+
+func (c *iAMVerifierClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, IAMVerifier_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMVerifierClient) VerifyBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[VerifyRequest, VerifyResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IAMVerifier_ServiceDesc.Streams[0], IAMVerifier_VerifyBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[VerifyRequest, VerifyResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IAMVerifier_VerifyBatchClient = grpc.BidiStreamingClient[VerifyRequest, VerifyResponse]
+
+func (c *iAMVerifierClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, IAMVerifier_Evaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IAMVerifierServer is the server API for IAMVerifier service.
+// All implementations must embed UnimplementedIAMVerifierServer
+// for forward compatibility.
+//
+// IAMVerifier verifies IAM policies and simulates requests against them.
+// Its messages mirror the JSON answers of the HTTP server.
+type IAMVerifierServer interface {
+	// Verify verifies a single policy.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// VerifyBatch verifies a stream of policies, answering each request in
+	// order; responses carry the id of their request.
+	VerifyBatch(grpc.BidiStreamingServer[VerifyRequest, VerifyResponse]) error
+	// Evaluate simulates a request against a policy.
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	mustEmbedUnimplementedIAMVerifierServer()
+}
+
+// UnimplementedIAMVerifierServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIAMVerifierServer struct{}
+
+func (UnimplementedIAMVerifierServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedIAMVerifierServer) VerifyBatch(grpc.BidiStreamingServer[VerifyRequest, VerifyResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method VerifyBatch not implemented")
+}
+func (UnimplementedIAMVerifierServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedIAMVerifierServer) mustEmbedUnimplementedIAMVerifierServer() {}
+func (UnimplementedIAMVerifierServer) testEmbeddedByValue()                     {}
+
+// UnsafeIAMVerifierServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IAMVerifierServer will
+// result in compilation errors.
+type UnsafeIAMVerifierServer interface {
+	mustEmbedUnimplementedIAMVerifierServer()
+}
+
+func RegisterIAMVerifierServer(s grpc.ServiceRegistrar, srv IAMVerifierServer) {
+	// If the following call pancis, it indicates UnimplementedIAMVerifierServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IAMVerifier_ServiceDesc, srv)
+}
+
+func _IAMVerifier_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMVerifierServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAMVerifier_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMVerifierServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAMVerifier_VerifyBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IAMVerifierServer).VerifyBatch(&grpc.GenericServerStream[VerifyRequest, VerifyResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IAMVerifier_VerifyBatchServer = grpc.BidiStreamingServer[VerifyRequest, VerifyResponse]
+
+func _IAMVerifier_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMVerifierServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAMVerifier_Evaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMVerifierServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IAMVerifier_ServiceDesc is the grpc.ServiceDesc for IAMVerifier service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IAMVerifier_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "iamverify.v1.IAMVerifier",
+	HandlerType: (*IAMVerifierServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Verify",
+			Handler:    _IAMVerifier_Verify_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _IAMVerifier_Evaluate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "VerifyBatch",
+			Handler:       _IAMVerifier_VerifyBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "iamverify/v1/verify.proto",
+}
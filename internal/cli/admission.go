@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"test3/iampolicy"
+)
+
+// admissionReview is the subset of a Kubernetes admission.k8s.io/v1
+// AdmissionReview the webhook reads and answers.
+type admissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *admissionRequest  `json:"request,omitempty"`
+	Response   *admissionResponse `json:"response,omitempty"`
+}
+
+type admissionRequest struct {
+	UID       string                 `json:"uid"`
+	Name      string                 `json:"name,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Operation string                 `json:"operation"`
+	Object    map[string]interface{} `json:"object"`
+}
+
+type admissionResponse struct {
+	UID      string           `json:"uid"`
+	Allowed  bool             `json:"allowed"`
+	Status   *admissionStatus `json:"status,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+type admissionStatus struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// admit answers an admission request: objects with an invalid policy or an
+// error finding are denied with the messages, other findings are returned
+// as warnings kubectl shows to the user.
+func admit(v *iampolicy.Verifier, request *admissionRequest) *admissionResponse {
+	response := &admissionResponse{UID: request.UID, Allowed: true}
+	if request.Operation != "CREATE" && request.Operation != "UPDATE" {
+		return response
+	}
+
+	var denials []string
+	for _, result := range v.VerifyKubernetesObject(request.Object) {
+		if result.Err != nil {
+			denials = append(denials, fmt.Sprintf("%s: %s", result.Source, result.Err))
+			continue
+		}
+		for _, finding := range result.Findings {
+			if finding.Suppression != nil {
+				continue
+			}
+			message := fmt.Sprintf("%s: %s", result.Source, finding)
+			if finding.Severity >= iampolicy.SeverityError {
+				denials = append(denials, message)
+			} else {
+				response.Warnings = append(response.Warnings, message)
+			}
+		}
+	}
+
+	if len(denials) > 0 {
+		response.Allowed = false
+		response.Status = &admissionStatus{
+			Code:    http.StatusForbidden,
+			Reason:  "Forbidden",
+			Message: "IAM policy verification failed: " + strings.Join(denials, "; "),
+		}
+	}
+	return response
+}
+
+func (s *server) handleAdmission(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+	var review admissionReview
+	if err := json.Unmarshal(body, &review); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid AdmissionReview: %s", err))
+		return
+	}
+	if review.Request == nil {
+		writeError(w, http.StatusBadRequest, "AdmissionReview has no request")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, admissionReview{
+		APIVersion: review.APIVersion,
+		Kind:       review.Kind,
+		Response:   admit(s.verifier, review.Request),
+	})
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdmissionWebhook(t *testing.T) {
+	ts := httptest.NewServer(newServer(newTestVerifier(t), defaultMaxRequestBytes).handler())
+	defer ts.Close()
+
+	trust := `{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Principal\": {\"Service\": \"ec2.amazonaws.com\"}, \"Action\": \"sts:AssumeRole\"}]}`
+	testCases := []struct {
+		name     string
+		object   string
+		allowed  bool
+		message  string
+		warnings int
+	}{
+		{
+			name:    "ACKRolePasses",
+			object:  `{"apiVersion": "iam.services.k8s.aws/v1alpha1", "kind": "Role", "spec": {"name": "app", "assumeRolePolicyDocument": "` + trust + `", "inlinePolicies": {"read": "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Action\": \"s3:GetObject\", \"Resource\": \"arn:aws:s3:::b/*\"}]}"}}}`,
+			allowed: true,
+		},
+		{
+			name:    "ACKRoleWildcardResource",
+			object:  `{"apiVersion": "iam.services.k8s.aws/v1alpha1", "kind": "Role", "spec": {"name": "app", "assumeRolePolicyDocument": "` + trust + `", "inlinePolicies": {"all": "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Action\": \"s3:GetObject\", \"Resource\": \"*\"}]}"}}}`,
+			message: `IAM policy verification failed: spec.inlinePolicies["all"]: [error] wildcard-resource: statement #1: Resource field contains a single asterisk`,
+		},
+		{
+			name:    "ACKPolicyInvalid",
+			object:  `{"apiVersion": "iam.services.k8s.aws/v1alpha1", "kind": "Policy", "spec": {"name": "p", "policyDocument": "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Action\": \"s3:GetObject\", \"Resource\": \"*\"}]}"}}`,
+			message: "IAM policy verification failed: spec.policyDocument: Effect field is missing",
+		},
+		{
+			name:     "UpboundRoleWarning",
+			object:   `{"apiVersion": "iam.aws.upbound.io/v1beta1", "kind": "Role", "spec": {"forProvider": {"assumeRolePolicy": "` + trust + `", "inlinePolicy": [{"name": "dup", "policy": "{\"Version\": \"2012-10-17\", \"Statement\": [{\"Effect\": \"Allow\", \"Action\": [\"s3:GetObject\", \"s3:GetObject\"], \"Resource\": \"arn:aws:s3:::b/*\"}]}"}]}}}`,
+			allowed:  true,
+			warnings: 1,
+		},
+		{
+			name:    "CrossplanePolicyWithoutResource",
+			object:  `{"apiVersion": "iam.aws.crossplane.io/v1beta1", "kind": "Policy", "spec": {"forProvider": {"name": "p", "document": "` + trust + `"}}}`,
+			message: "IAM policy verification failed: spec.forProvider.document: Resource field is missing",
+		},
+		{
+			name:    "OtherKind",
+			object:  `{"apiVersion": "v1", "kind": "ConfigMap", "data": {"policy": "*"}}`,
+			allowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := postAdmissionReview(t, ts.URL, "CREATE", tc.object)
+			if response.UID != "705ab4f5-6393-11e8-b7cc-42010a800002" {
+				t.Errorf("Expected the request UID in the response, but got %q", response.UID)
+			}
+			if response.Allowed != tc.allowed {
+				t.Fatalf("Expected allowed %t, but got %+v", tc.allowed, response)
+			}
+			if tc.message != "" && (response.Status == nil || response.Status.Message != tc.message || response.Status.Code != http.StatusForbidden) {
+				t.Errorf("Expected denial %q, but got %+v", tc.message, response.Status)
+			}
+			if len(response.Warnings) != tc.warnings {
+				t.Errorf("Expected %d warnings, but got %v", tc.warnings, response.Warnings)
+			}
+		})
+	}
+
+	// Deletions are never denied.
+	object := testCases[1].object
+	if response := postAdmissionReview(t, ts.URL, "DELETE", object); !response.Allowed {
+		t.Errorf("Expected DELETE to be allowed, but got %+v", response)
+	}
+
+	response, err := http.Post(ts.URL+"/v1/admission", "application/json", strings.NewReader(`{"apiVersion": "admission.k8s.io/v1", "kind": "AdmissionReview"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a review without request, but got %d", response.StatusCode)
+	}
+}
+
+func postAdmissionReview(t *testing.T, url, operation, object string) *admissionResponse {
+	t.Helper()
+	review := `{"apiVersion": "admission.k8s.io/v1", "kind": "AdmissionReview", "request": {"uid": "705ab4f5-6393-11e8-b7cc-42010a800002", "operation": "` + operation + `", "object": ` + object + `}}`
+	response, err := http.Post(url+"/v1/admission", "application/json", strings.NewReader(review))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, but got %d", response.StatusCode)
+	}
+
+	var answer admissionReview
+	if err := json.NewDecoder(response.Body).Decode(&answer); err != nil {
+		t.Fatal(err)
+	}
+	if answer.APIVersion != "admission.k8s.io/v1" || answer.Kind != "AdmissionReview" || answer.Response == nil {
+		t.Fatalf("Expected an AdmissionReview response, but got %+v", answer)
+	}
+	return answer.Response
+}
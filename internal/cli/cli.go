@@ -0,0 +1,82 @@
+// Package cli implements the verify_iam command line on top of the
+// iampolicy package.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// command is a subcommand of the verify_iam command line.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands returns the subcommands in the order help lists them.
+func commands() []command {
+	return []command{
+		{"verify", "verify policies against the rules (the default command)", runVerify},
+		{"fmt", "rewrite policy files in canonical form", runFmt},
+		{"diff", "report the access added and removed between two versions of a policy", runDiff},
+		{"evaluate", "decide whether a policy allows a request", runEvaluate},
+		{"explain", "describe the rules and how they are configured", runExplain},
+		{"serve", "serve verification over HTTP", runServe},
+		{"serve-grpc", "serve verification over gRPC", runServeGRPC},
+		{"optimize", "print a smaller policy granting the same access", runOptimize},
+		{"compare", "decide whether a policy grants a subset of the access of another", runCompare},
+		{"generate", "generate a least-privilege policy from CloudTrail logs", runGenerate},
+		{"unused", "report the permissions of a policy unused in CloudTrail logs", runUnused},
+	}
+}
+
+// Main runs the verify_iam command line with the given arguments, not
+// including the program name, and returns the exit status. Without a
+// command name the arguments are those of verify.
+func Main(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			return runHelp(args[1:])
+		}
+		for _, c := range commands() {
+			if c.name == args[0] {
+				return c.run(args[1:])
+			}
+		}
+	}
+	return runVerify(args)
+}
+
+// runHelp lists the commands, or prints the usage of the named one.
+func runHelp(args []string) int {
+	if len(args) > 0 {
+		for _, c := range commands() {
+			if c.name == args[0] {
+				return c.run([]string{"-help"})
+			}
+		}
+		fmt.Printf("Error: unknown command '%s'\n", args[0])
+		return 2
+	}
+
+	fmt.Println("Usage: go run ./cmd/verify_iam <command> [flags] [arguments]")
+	fmt.Println("\nCommands:")
+	for _, c := range commands() {
+		fmt.Printf("  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Println("\nWithout a command the arguments are those of verify.")
+	fmt.Println("Run \"go run ./cmd/verify_iam help <command>\" for the flags of a command.")
+	return 0
+}
+
+// usageStatus returns the exit status for a flag parsing error: 0 when help
+// was asked for, 2 otherwise.
+func usageStatus(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 2
+}
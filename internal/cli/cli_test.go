@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandLine(t *testing.T) {
+	dir := t.TempDir()
+	passing := filepath.Join(dir, "passing.json")
+	warning := filepath.Join(dir, "warning.json")
+	failing := filepath.Join(dir, "failing.json")
+	for path, content := range map[string]string{
+		passing: `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`,
+		warning: `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:GetObject"], "Resource": "arn:aws:s3:::data/*"}]}`,
+		failing: `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"help"}, 0},
+		{[]string{"--help"}, 0},
+		{[]string{"help", "evaluate"}, 0},
+		{[]string{"help", "nope"}, 2},
+		{[]string{"verify", "-help"}, 0},
+		{[]string{"fmt", "-h"}, 0},
+		{[]string{"diff", "--help"}, 0},
+		{[]string{"explain", "-help"}, 0},
+		{[]string{"serve", "-help"}, 0},
+		{[]string{"verify", "-no-such-flag", passing}, 2},
+		{[]string{passing}, 0},
+		{[]string{"verify", passing}, 0},
+		{[]string{"verify", failing}, 1},
+		{[]string{"verify", "-quiet", failing}, 1},
+		{[]string{"verify", "-verbose", passing}, 0},
+		{[]string{"verify", "-format", "json", failing}, 1},
+		{[]string{"verify", "-format", "json", filepath.Join(dir, "missing.json")}, 1},
+		{[]string{"verify", "-format", "yaml", passing}, 2},
+		{[]string{"verify", "-quiet", "-verbose", passing}, 2},
+		{[]string{"verify", warning}, 0},
+		{[]string{"verify", "-severity", "warning", warning}, 1},
+		{[]string{"verify", "-severity", "fatal", warning}, 2},
+		{[]string{"verify", "-kind", "managed", passing}, 0},
+	}
+	for _, tc := range testCases {
+		if status := Main(tc.args); status != tc.expected {
+			t.Errorf("verify_iam %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+
+	"test3/iampolicy"
+)
+
+func printRequest(request *iampolicy.AccessRequest) {
+	if request.Principal != "" {
+		fmt.Printf("  principal: %s\n", request.Principal)
+	}
+	fmt.Printf("  action:    %s\n", request.Action)
+	if request.Resource != "" {
+		fmt.Printf("  resource:  %s\n", request.Resource)
+	}
+	conditions := make([]string, 0, len(request.Conditions))
+	for condition := range request.Conditions {
+		conditions = append(conditions, condition)
+	}
+	sort.Strings(conditions)
+	for _, condition := range conditions {
+		fmt.Printf("  condition: %s is %t\n", condition, request.Conditions[condition])
+	}
+}
+
+// runCompare implements the compare subcommand and returns the exit status:
+// 0 when the relation between the policies is the expected one, 1 when not.
+func runCompare(args []string) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	expect := flags.String("expect", "equivalent", "relation required of the first policy: equivalent, subset or superset")
+	jsonOutput := flags.Bool("json", false, "print the comparison as JSON")
+	useCatalog := flags.Bool("use-catalog", false, "take the action catalog to list every action of its complete services")
+	catalogFile := flags.String("catalog", "", "action catalog file, one service:action per line; implies -use-catalog")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam compare [-expect equivalent|subset|superset] [-use-catalog] [-catalog <file>] [-json] <a_json_file> <b_json_file>")
+		fmt.Println("\nDecides whether policy A grants a subset of, a superset of or the same access as policy B,")
+		fmt.Println("with a request allowed by only one of them when they differ.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	var accepted []iampolicy.Relation
+	switch *expect {
+	case "equivalent":
+		accepted = []iampolicy.Relation{iampolicy.Equivalent}
+	case "subset":
+		accepted = []iampolicy.Relation{iampolicy.Equivalent, iampolicy.Subset}
+	case "superset":
+		accepted = []iampolicy.Relation{iampolicy.Equivalent, iampolicy.Superset}
+	default:
+		fmt.Printf("Error: unknown relation '%s'\n", *expect)
+		return 2
+	}
+
+	var catalog *iampolicy.Catalog
+	if *useCatalog || *catalogFile != "" {
+		var err error
+		catalog, err = iampolicy.LoadCatalog(*catalogFile)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return 2
+		}
+	}
+	var policies [2]*iampolicy.Policy
+	for i, path := range flags.Args() {
+		var err error
+		policies[i], err = loadPolicyFile(path)
+		if err != nil {
+			fmt.Printf("Error: %s: %s\n", path, err)
+			return 2
+		}
+	}
+	result, err := iampolicy.Compare(policies[0], policies[1], catalog)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	if *jsonOutput {
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"relation": result.Relation.String(),
+			"onlyA":    result.OnlyA,
+			"onlyB":    result.OnlyB,
+		}, "", "    ")
+		fmt.Println(string(out))
+	} else {
+		a, b := flags.Arg(0), flags.Arg(1)
+		switch result.Relation {
+		case iampolicy.Equivalent:
+			fmt.Printf("%s and %s grant the same access\n", a, b)
+		case iampolicy.Subset:
+			fmt.Printf("%s grants a subset of the access of %s\n", a, b)
+		case iampolicy.Superset:
+			fmt.Printf("%s grants a superset of the access of %s\n", a, b)
+		default:
+			fmt.Printf("%s and %s grant incomparable access\n", a, b)
+		}
+		if result.OnlyA != nil {
+			fmt.Printf("Allowed by %s only:\n", a)
+			printRequest(result.OnlyA)
+		}
+		if result.OnlyB != nil {
+			fmt.Printf("Allowed by %s only:\n", b)
+			printRequest(result.OnlyB)
+		}
+	}
+
+	for _, r := range accepted {
+		if result.Relation == r {
+			return 0
+		}
+	}
+	return 1
+}
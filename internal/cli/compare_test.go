@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunCompare(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	if err := os.WriteFile(a, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["sqs:SendMessage", "sqs:SendMessageBatch"], "Resource": "*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sqs:Send*", "Resource": "*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{a, b}, 1},
+		{[]string{"-expect", "subset", a, b}, 0},
+		{[]string{"-expect", "superset", a, b}, 1},
+		{[]string{"-use-catalog", a, b}, 0},
+		{[]string{"-expect", "bigger", a, b}, 2},
+		{[]string{a}, 2},
+	}
+	for _, tc := range testCases {
+		if status := runCompare(tc.args); status != tc.expected {
+			t.Errorf("compare %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
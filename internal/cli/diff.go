@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"test3/iampolicy"
+)
+
+// runDiff implements the diff subcommand and returns the exit status: 0 when
+// both policies grant the same access, 1 when they differ.
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	catalogFile := flags.String("catalog", "", "action catalog file, one service:action per line (default: embedded catalog)")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam diff [-catalog <file>] <old_json_file> <new_json_file>")
+		fmt.Println("\nReports the access added and removed between two versions of a policy, one action per line.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	catalog, err := iampolicy.LoadCatalog(*catalogFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	var policies [2]*iampolicy.Policy
+	for i, path := range flags.Args() {
+		policies[i], err = loadPolicyFile(path)
+		if err != nil {
+			fmt.Printf("Error: %s: %s\n", path, err)
+			return 2
+		}
+	}
+
+	for _, policy := range policies {
+		for _, statement := range policy.Statements {
+			if statement.UsesNegation() {
+				fmt.Printf("Note: statement %s uses NotAction or NotResource and is not compared; use the compare subcommand\n", statement.Name())
+			}
+		}
+	}
+
+	added, removed := iampolicy.Diff(policies[0], policies[1], catalog)
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Println("No change in access")
+		return 0
+	}
+	if len(added) > 0 {
+		fmt.Println("Added access:")
+		for _, line := range iampolicy.FormatGrants(added) {
+			fmt.Printf("  + %s\n", strings.Replace(line, "Deny ", "lifted Deny ", 1))
+		}
+	}
+	if len(removed) > 0 {
+		fmt.Println("Removed access:")
+		for _, line := range iampolicy.FormatGrants(removed) {
+			fmt.Printf("  - %s\n", strings.Replace(line, "Deny ", "new Deny ", 1))
+		}
+	}
+	return 1
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.json")
+	after := filepath.Join(dir, "after.json")
+	if err := os.WriteFile(before, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(after, []byte(`{"PolicyName": "root", "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["sqs:SendMessage"], "Resource": ["*"]}]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := runDiff([]string{before, after}); status != 0 {
+		t.Errorf("Expected status 0 for equivalent policies, but got %d", status)
+	}
+	if err := os.WriteFile(after, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sqs:Send*", "Resource": "*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status := runDiff([]string{before, after}); status != 1 {
+		t.Errorf("Expected status 1 for different policies, but got %d", status)
+	}
+	if err := os.WriteFile(after, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "*"}, {"Effect": "Deny", "Action": "sqs:SendMessage", "Resource": "arn:aws:sqs:us-east-1:1:q"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status := runDiff([]string{before, after}); status != 1 {
+		t.Errorf("Expected status 1 for an added Deny, but got %d", status)
+	}
+	if status := runDiff([]string{before}); status != 2 {
+		t.Errorf("Expected status 2 for missing argument, but got %d", status)
+	}
+}
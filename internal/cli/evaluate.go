@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"test3/iampolicy"
+)
+
+// runEvaluate implements the evaluate subcommand and returns the exit
+// status: 0 when the policy allows the request, 1 when it denies it.
+func runEvaluate(args []string) int {
+	flags := flag.NewFlagSet("evaluate", flag.ContinueOnError)
+	var request iampolicy.Request
+	flags.StringVar(&request.Principal, "principal", "", "principal making the request as Type:value, e.g. Service:ec2.amazonaws.com; for trust policies")
+	flags.StringVar(&request.Action, "action", "", "action requested, e.g. s3:GetObject (required)")
+	flags.StringVar(&request.Resource, "resource", "", "ARN of the resource requested; for permission policies")
+	context := flags.String("context", "", "condition key values as a JSON object, e.g. {\"aws:SourceIp\": \"10.0.0.1\"}")
+	var output outputFlags
+	output.register(flags)
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam evaluate -action <action> [-resource <arn>] [-principal <Type:value>] [-context <json>] [-format text|json] [-quiet] <path_to_json_file> | -")
+		fmt.Println("\nDecides whether a policy allows a request and names the statements allowing or denying it.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	inputs := inputArgs(flags)
+	if len(inputs) != 1 || request.Action == "" {
+		flags.Usage()
+		return 2
+	}
+	if err := output.check(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	if *context != "" {
+		if err := json.Unmarshal([]byte(*context), &request.Context); err != nil {
+			fmt.Printf("Error: -context is not a JSON object: %s\n", err)
+			return 2
+		}
+	}
+
+	policy, err := loadPolicyFile(inputs[0])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	result, err := iampolicy.Evaluate(policy, request)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	if output.json() {
+		printJSON(result)
+	}
+	if output.text() {
+		fmt.Printf("Decision: %s\n", result.Decision)
+		if len(result.AllowedBy) > 0 {
+			fmt.Printf("Allowed by: %s\n", strings.Join(result.AllowedBy, ", "))
+		}
+		if len(result.DeniedBy) > 0 {
+			fmt.Printf("Denied by: %s\n", strings.Join(result.DeniedBy, ", "))
+		}
+	}
+	if result.Decision != iampolicy.Allowed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunEvaluate(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"Version": "2012-10-17", "Statement": [
+		{"Sid": "Read", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"},
+		{"Sid": "Office", "Effect": "Deny", "Action": "s3:*", "Resource": "*", "Condition": {"NotIpAddress": {"aws:SourceIp": "10.0.0.0/8"}}}
+	]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"-action", "s3:GetObject", "-resource", "arn:aws:s3:::data/a", "-context", `{"aws:SourceIp": "10.1.2.3"}`, policy}, 0},
+		{[]string{"-format", "json", "-action", "s3:GetObject", "-resource", "arn:aws:s3:::data/a", "-context", `{"aws:SourceIp": "192.0.2.1"}`, policy}, 1},
+		{[]string{"-quiet", "-action", "s3:PutObject", "-resource", "arn:aws:s3:::data/a", "-context", `{"aws:SourceIp": "10.1.2.3"}`, policy}, 1},
+		{[]string{"-context", `["aws:SourceIp"]`, "-action", "s3:GetObject", policy}, 2},
+		{[]string{"-resource", "arn:aws:s3:::data/a", policy}, 2},
+		{[]string{"-action", "s3:GetObject", filepath.Join(dir, "missing.json")}, 2},
+		{[]string{"-action", "s3:GetObject", "-context", `{"aws:SourceIp": "10.1.2.3"}`, "-format", "xml", policy}, 2},
+	}
+	for _, tc := range testCases {
+		if status := runEvaluate(tc.args); status != tc.expected {
+			t.Errorf("evaluate %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
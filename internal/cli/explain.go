@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"test3/iampolicy"
+)
+
+// runExplain implements the explain subcommand and returns the exit status.
+func runExplain(args []string) int {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file")
+	rulesDir := flags.String("rules", "", "directory of declarative rules to load")
+	output := outputFlags{}
+	flags.StringVar(&output.format, "format", textFormat, "output format: text or json")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam explain [-config <config_file>] [-rules <dir>] [-format text|json] [<rule_id>...]")
+		fmt.Println("\nDescribes the rules with the severity they report and whether they run under the configuration.")
+		fmt.Println("Without rule IDs every registered rule is described.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if err := output.check(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	cfg, err := iampolicy.LoadConfig(*configFile, *rulesDir)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	v, err := cfg.Verifier("")
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	explanations, err := v.ExplainRules(flags.Args())
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	if output.json() {
+		printJSON(explanations)
+		return 0
+	}
+	for _, rule := range explanations {
+		state := rule.Severity.String()
+		if !rule.Enabled {
+			state += ", disabled"
+		}
+		fmt.Printf("%s (%s)\n    %s\n", rule.ID, state, rule.Description)
+	}
+	return 0
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{nil, 0},
+		{[]string{"wildcard-resource"}, 0},
+		{[]string{"-format", "json", "sid-required"}, 0},
+		{[]string{"no-such-rule"}, 2},
+		{[]string{"-format", "xml"}, 2},
+		{[]string{"-config", "missing.yaml"}, 2},
+	}
+	for _, tc := range testCases {
+		if status := runExplain(tc.args); status != tc.expected {
+			t.Errorf("explain %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"test3/iampolicy"
+)
+
+// runFmt implements the fmt subcommand and returns the exit status.
+func runFmt(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	check := flags.Bool("check", false, "report files that are not formatted instead of rewriting them")
+	minify := flags.Bool("minify", false, "write policies without whitespace to fit size quotas")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam fmt [-check] [-minify] <path_to_json_file>...")
+		fmt.Println("\nRewrites policy files in canonical form: stable key order, sorted and deduplicated Action and Resource lists.")
+		fmt.Println("Standard input, read for \"-\" or when no file is given and input is piped, is written to standard output.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	paths := inputArgs(flags)
+	if len(paths) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	status := 0
+	for _, path := range paths {
+		fileData, err := readInputFile(path)
+		if err != nil {
+			fmt.Printf("%s: %s\n", path, err)
+			status = 2
+			continue
+		}
+		formatted, err := iampolicy.Format(fileData, *minify)
+		if err != nil {
+			fmt.Printf("%s: %s\n", path, err)
+			status = 2
+			continue
+		}
+		if path == stdinPath && !*check {
+			os.Stdout.Write(formatted)
+			continue
+		}
+		if bytes.Equal(formatted, fileData) {
+			continue
+		}
+		if *check {
+			fmt.Printf("%s: not formatted\n", path)
+			if status == 0 {
+				status = 1
+			}
+			continue
+		}
+		if err := os.WriteFile(path, formatted, 0644); err != nil {
+			fmt.Printf("%s: %s\n", path, err)
+			status = 2
+			continue
+		}
+		fmt.Printf("%s: formatted\n", path)
+	}
+	return status
+}
+
+// writePolicy writes a policy document, or a {PolicyName, PolicyDocument}
+// wrapper when name is not empty, in canonical form to a file, or to
+// standard output when path is empty.
+func writePolicy(document map[string]interface{}, name, path string) error {
+	var value interface{} = document
+	if name != "" {
+		value = map[string]interface{}{"PolicyName": name, "PolicyDocument": document}
+	}
+	fileData, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	formatted, err := iampolicy.Format(fileData, false)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(formatted)
+		return err
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const unformattedPolicyJSON = `{"Statement": [{"Resource": ["arn:aws:s3:::b", "arn:aws:s3:::a", "arn:aws:s3:::b"], "Action": "s3:ListBucket", "Effect": "Allow", "Sid": "List"}], "Version": "2012-10-17"}`
+
+const formattedPolicyJSON = `{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Sid": "List",
+            "Effect": "Allow",
+            "Action": [
+                "s3:ListBucket"
+            ],
+            "Resource": [
+                "arn:aws:s3:::a",
+                "arn:aws:s3:::b"
+            ]
+        }
+    ]
+}
+`
+
+func TestRunFmt(t *testing.T) {
+	dir := t.TempDir()
+	formatted := filepath.Join(dir, "formatted.json")
+	unformatted := filepath.Join(dir, "unformatted.json")
+	if err := os.WriteFile(formatted, []byte(formattedPolicyJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unformatted, []byte(unformattedPolicyJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := runFmt([]string{"-check", formatted}); status != 0 {
+		t.Errorf("Expected status 0 for formatted file, but got %d", status)
+	}
+	if status := runFmt([]string{"-check", formatted, unformatted}); status != 1 {
+		t.Errorf("Expected status 1 for unformatted file, but got %d", status)
+	}
+	if status := runFmt([]string{unformatted}); status != 0 {
+		t.Errorf("Expected status 0 after rewriting, but got %d", status)
+	}
+	fileData, err := os.ReadFile(unformatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(fileData) != formattedPolicyJSON {
+		t.Errorf("Expected file to be rewritten in canonical form, but got:\n%s", fileData)
+	}
+	if status := runFmt([]string{filepath.Join(dir, "missing.json")}); status != 2 {
+		t.Errorf("Expected status 2 for missing file, but got %d", status)
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"test3/iampolicy"
+)
+
+// runGenerate implements the generate subcommand and returns the exit status.
+func runGenerate(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	role := flags.String("role", "", "name or ARN of the role whose events to use")
+	name := flags.String("name", "generated-policy", "PolicyName of the generated policy")
+	output := flags.String("o", "", "write the generated policy to this file instead of standard output")
+	current := flags.String("compare", "", "current policy of the role, to report the permissions it grants but the role did not use")
+	catalogFile := flags.String("catalog", "", "action catalog file telling which actions have no resource-level permissions (default: embedded catalog)")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam generate -role <name_or_arn> [-name <policy_name>] [-o <file>] [-compare <policy_file>] [-catalog <file>] <cloudtrail_file_or_dir>...")
+		fmt.Println("\nGenerates a least-privilege policy from the actions and resources a role used in CloudTrail logs (.json or .json.gz).")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if *role == "" || flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	catalog, err := iampolicy.LoadCatalog(*catalogFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	generated, err := iampolicy.GenerateFromCloudTrail(flags.Args(), *role, *name, catalog)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	if err := writePolicy(generated.Document, *name, *output); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	if *output != "" {
+		fmt.Printf("Wrote generated policy to %s\n", *output)
+	}
+
+	if *current == "" {
+		return 0
+	}
+	policy, err := loadPolicyFile(*current)
+	if err != nil {
+		fmt.Printf("Error: %s: %s\n", *current, err)
+		return 2
+	}
+	_, unused := iampolicy.Diff(policy, generated, catalog)
+	if len(unused) == 0 {
+		fmt.Printf("\nEvery permission of %s was used\n", *current)
+		return 0
+	}
+	fmt.Printf("\nPermissions of %s not used in the logs:\n", *current)
+	for _, line := range iampolicy.FormatGrants(unused) {
+		fmt.Printf("  - %s\n", line)
+	}
+	return 0
+}
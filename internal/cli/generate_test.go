@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const cloudTrailJSON = `{"Records": [
+  {"eventTime": "2024-05-01T10:00:00Z", "eventSource": "s3.amazonaws.com", "eventName": "GetObject", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:s3:::data/report.csv"}, {"ARN": "arn:aws:s3:::data"}]},
+  {"eventTime": "2024-05-01T10:01:00Z", "eventSource": "s3.amazonaws.com", "eventName": "PutObject", "awsRegion": "us-east-1", "recipientAccountId": "123456789012",
+   "userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/app", "userName": "app"}}},
+   "resources": [{"ARN": "arn:aws:s3:::data/report.csv"}, {"ARN": "arn:aws:s3:::data"}]}
+]}`
+
+// writeCloudTrail writes a log file in a directory and returns the
+// directory.
+func writeCloudTrail(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(cloudTrailJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRunGenerate(t *testing.T) {
+	dir := writeCloudTrail(t)
+	output := filepath.Join(t.TempDir(), "generated.json")
+	current := filepath.Join(t.TempDir(), "current.json")
+	if err := os.WriteFile(current, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "arn:aws:s3:::data/*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := runGenerate([]string{"-role", "app", "-o", output, "-compare", current, dir}); status != 0 {
+		t.Fatalf("Expected status 0, but got %d", status)
+	}
+	report, err := newTestVerifier(t).VerifyFile(output)
+	if err != nil || !report.Passed {
+		t.Errorf("Expected generated policy to verify, got %+v, %v", report, err)
+	}
+	if status := runGenerate([]string{dir}); status != 2 {
+		t.Errorf("Expected status 2 without -role, but got %d", status)
+	}
+}
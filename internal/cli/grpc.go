@@ -0,0 +1,200 @@
+package cli
+
+//go:generate protoc --proto_path=../../proto --go_out=../.. --go_opt=module=test3 --go-grpc_out=../.. --go-grpc_opt=module=test3 iamverify/v1/verify.proto
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"test3/iampolicy"
+	"test3/iamverifypb"
+)
+
+// grpcService implements the IAMVerifier gRPC service defined in
+// proto/iamverify/v1/verify.proto.
+type grpcService struct {
+	iamverifypb.UnimplementedIAMVerifierServer
+	verifier *iampolicy.Verifier
+}
+
+func (s *grpcService) Verify(ctx context.Context, request *iamverifypb.VerifyRequest) (*iamverifypb.VerifyResponse, error) {
+	return s.verify(request), nil
+}
+
+func (s *grpcService) VerifyBatch(stream iamverifypb.IAMVerifier_VerifyBatchServer) error {
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(s.verify(request)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *grpcService) Evaluate(ctx context.Context, request *iamverifypb.EvaluateRequest) (*iamverifypb.EvaluateResponse, error) {
+	access := request.GetRequest()
+	result, err := evaluatePolicyInput(request.GetPolicy(), iampolicy.Request{
+		Principal: access.GetPrincipal(),
+		Action:    access.GetAction(),
+		Resource:  access.GetResource(),
+		Context:   access.GetContext().AsMap(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &iamverifypb.EvaluateResponse{
+		Decision:  result.Decision.String(),
+		AllowedBy: result.AllowedBy,
+		DeniedBy:  result.DeniedBy,
+	}, nil
+}
+
+// verify answers a verify request. Inputs holding no single policy are
+// answered with an error rather than failing the call, so that one bad
+// input does not end a batch.
+func (s *grpcService) verify(request *iamverifypb.VerifyRequest) *iamverifypb.VerifyResponse {
+	result, err := verifyPolicyText(s.verifier, []byte(request.GetPolicy()))
+	if err != nil {
+		return &iamverifypb.VerifyResponse{Id: request.GetId(), Error: err.Error()}
+	}
+	response := &iamverifypb.VerifyResponse{Id: request.GetId(), Result: result.Result, Kind: result.Kind, Error: result.Error}
+	for _, finding := range result.Findings {
+		response.Findings = append(response.Findings, findingMessage(finding))
+	}
+	return response
+}
+
+func findingMessage(finding iampolicy.Finding) *iamverifypb.Finding {
+	message := &iamverifypb.Finding{
+		Rule:      finding.RuleID,
+		Severity:  finding.Severity.String(),
+		Message:   finding.Message,
+		Statement: int32(finding.Statement),
+		Sid:       finding.Sid,
+	}
+	if s := finding.Suppression; s != nil {
+		message.Suppression = &iamverifypb.Suppression{Rule: s.Rule, Sid: s.Sid, Paths: s.Paths, Reason: s.Reason, Expires: s.Expires}
+	}
+	return message
+}
+
+// newGRPCServer returns a gRPC server offering the IAMVerifier service,
+// server reflection and the standard health service.
+func newGRPCServer(v *iampolicy.Verifier, options ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(options...)
+	iamverifypb.RegisterIAMVerifierServer(server, &grpcService{verifier: v})
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(iamverifypb.IAMVerifier_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	return server, healthServer
+}
+
+// serveGRPC answers calls on the listener until ctx is done, then reports
+// the service as not serving and waits up to timeout for calls in progress.
+func serveGRPC(ctx context.Context, server *grpc.Server, healthServer *health.Server, listener net.Listener, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	healthServer.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		server.Stop()
+	}
+	return nil
+}
+
+// runServeGRPC implements the serve-grpc subcommand and returns the exit
+// status.
+func runServeGRPC(args []string) int {
+	flags := flag.NewFlagSet("serve-grpc", flag.ContinueOnError)
+	addr := flags.String("addr", ":9090", "address to listen on")
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file")
+	rulesDir := flags.String("rules", "", "directory of declarative rules to load")
+	maxBytes := flags.Int("max-message", defaultMaxRequestBytes, "maximum size of a request message in bytes")
+	timeout := flags.Duration("shutdown-timeout", 10*time.Second, "time to let calls in progress finish on shutdown")
+	certFile := flags.String("tls-cert", "", "TLS certificate file")
+	keyFile := flags.String("tls-key", "", "TLS private key file")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam serve-grpc [-addr <host:port>] [-config <config_file>] [-rules <dir>] [-max-message <bytes>] [-shutdown-timeout <duration>] [-tls-cert <file> -tls-key <file>]")
+		fmt.Println("\nServes the iamverify.v1.IAMVerifier gRPC service with server reflection and health checking.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	v, err := serverVerifier(*configFile, *rulesDir)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	options := []grpc.ServerOption{grpc.MaxRecvMsgSize(*maxBytes)}
+	if (*certFile == "") != (*keyFile == "") {
+		fmt.Println("Error: -tls-cert and -tls-key must be used together")
+		return 2
+	}
+	if *certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(*certFile, *keyFile)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return 2
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Listening on %s\n", listener.Addr())
+	server, healthServer := newGRPCServer(v, options...)
+	if err := serveGRPC(ctx, server, healthServer, listener, *timeout); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	fmt.Println("Server stopped")
+	return 0
+}
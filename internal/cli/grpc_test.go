@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"test3/iamverifypb"
+)
+
+// startGRPCServer serves the gRPC API on an in-memory listener and returns
+// a connection to it. The server shuts down when the test ends.
+func startGRPCServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server, healthServer := newGRPCServer(newTestVerifier(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveGRPC(ctx, server, healthServer, listener, time.Second)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected clean shutdown, but got %s", err)
+		}
+	})
+	return conn
+}
+
+func TestGRPCVerify(t *testing.T) {
+	client := iamverifypb.NewIAMVerifierClient(startGRPCServer(t))
+	ctx := context.Background()
+
+	response, err := client.Verify(ctx, &iamverifypb.VerifyRequest{
+		Id:     "a",
+		Policy: `{"Version": "2012-10-17", "Statement": [{"Sid": "All", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &iamverifypb.Finding{Rule: "wildcard-resource", Severity: "error", Message: "Resource field contains a single asterisk", Statement: 0, Sid: "All"}
+	if response.Id != "a" || response.Result || response.Kind != "inline" || len(response.Findings) != 1 {
+		t.Fatalf("Unexpected response %v", response)
+	}
+	if got := response.Findings[0]; got.Rule != expected.Rule || got.Severity != expected.Severity || got.Message != expected.Message || got.Sid != expected.Sid {
+		t.Errorf("Expected finding %v, but got %v", expected, got)
+	}
+}
+
+func TestGRPCVerifyBatch(t *testing.T) {
+	client := iamverifypb.NewIAMVerifierClient(startGRPCServer(t))
+	stream, err := client.VerifyBatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []*iamverifypb.VerifyRequest{
+		{Id: "valid", Policy: `{"PolicyName": "p", "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}]}}`},
+		{Id: "broken", Policy: `{"Version": `},
+		{Id: "invalid", Policy: `{"Version": "2012-10-17", "Statement": [{"Action": "s3:GetObject", "Resource": "*"}]}`},
+	}
+	for _, request := range requests {
+		if err := stream.Send(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids, messages []string
+	var results []bool
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, response.Id)
+		results = append(results, response.Result)
+		messages = append(messages, response.Error)
+	}
+	if !reflect.DeepEqual(ids, []string{"valid", "broken", "invalid"}) || !reflect.DeepEqual(results, []bool{true, false, false}) {
+		t.Errorf("Unexpected batch responses %v %v", ids, results)
+	}
+	if messages[0] != "" || messages[1] == "" || messages[2] != "Effect field is missing" {
+		t.Errorf("Unexpected batch errors %q", messages)
+	}
+}
+
+func TestGRPCEvaluate(t *testing.T) {
+	client := iamverifypb.NewIAMVerifierClient(startGRPCServer(t))
+	ctx := context.Background()
+	policy := `{"Version": "2012-10-17", "Statement": [{"Sid": "Write", "Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::b/*", "Condition": {"Bool": {"aws:SecureTransport": "true"}}}]}`
+
+	values, err := structpb.NewStruct(map[string]interface{}{"aws:SecureTransport": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Evaluate(ctx, &iamverifypb.EvaluateRequest{
+		Policy:  policy,
+		Request: &iamverifypb.AccessRequest{Action: "s3:PutObject", Resource: "arn:aws:s3:::b/k", Context: values},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Decision != "allowed" || !reflect.DeepEqual(response.AllowedBy, []string{"Write"}) {
+		t.Errorf("Unexpected evaluation %v", response)
+	}
+
+	response, err = client.Evaluate(ctx, &iamverifypb.EvaluateRequest{
+		Policy:  policy,
+		Request: &iamverifypb.AccessRequest{Action: "s3:PutObject", Resource: "arn:aws:s3:::b/k"},
+	})
+	if err != nil || response.Decision != "implicitDeny" {
+		t.Errorf("Expected implicitDeny without the condition key, but got %v, %v", response, err)
+	}
+
+	_, err = client.Evaluate(ctx, &iamverifypb.EvaluateRequest{Policy: `{"Version": "2012-10-17"}`, Request: &iamverifypb.AccessRequest{Action: "s3:PutObject"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an invalid policy, but got %v", err)
+	}
+}
+
+func TestGRPCHealthAndReflection(t *testing.T) {
+	conn := startGRPCServer(t)
+	ctx := context.Background()
+
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "iamverify.v1.IAMVerifier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, but got %s", response.Status)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	answer, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var services []string
+	for _, service := range answer.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	found := false
+	for _, name := range services {
+		found = found || name == "iamverify.v1.IAMVerifier"
+	}
+	if !found {
+		t.Errorf("Expected reflection to list iamverify.v1.IAMVerifier, but got %v", services)
+	}
+	stream.CloseSend()
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"test3/iampolicy"
+)
+
+// stdinPath names standard input in place of an input file.
+const stdinPath = "-"
+
+// stdin is read for the "-" path.
+var stdin = os.Stdin
+
+// readInputFile reads an input file, or standard input for "-".
+func readInputFile(path string) ([]byte, error) {
+	if path == stdinPath {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// stdinPiped reports whether standard input is redirected from a pipe or a
+// file rather than a terminal; commands taking a single input file then read
+// it when no file is given.
+func stdinPiped() bool {
+	info, err := stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// inputArgs returns the input files named on the command line, standing in
+// standard input for a missing one when it is piped.
+func inputArgs(flags *flag.FlagSet) []string {
+	if flags.NArg() == 0 && stdinPiped() {
+		return []string{stdinPath}
+	}
+	return flags.Args()
+}
+
+// inputName describes an input file in messages.
+func inputName(path string) string {
+	if path == stdinPath {
+		return "standard input"
+	}
+	return path
+}
+
+// loadPolicyFile reads a file holding a single policy in any input format
+// and returns the validated, parsed policy.
+func loadPolicyFile(path string) (*iampolicy.Policy, error) {
+	fileData, err := readInputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return iampolicy.Parse(fileData)
+}
+
+// verifyInputFile verifies an input file, or standard input for "-", which
+// has no sidecar annotation file.
+func verifyInputFile(v *iampolicy.Verifier, path string) (*iampolicy.FileReport, error) {
+	if path != stdinPath {
+		return v.VerifyFile(path)
+	}
+	fileData, err := readInputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return v.VerifyInput(path, fileData)
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdinInput(t *testing.T) {
+	passing := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`
+	failing := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`
+	roleResponse := `{"RoleName": "app", "PolicyName": "read", "PolicyDocument": "%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22s3%3AGetObject%22%2C%22Resource%22%3A%22arn%3Aaws%3As3%3A%3A%3Adata%2F%2A%22%7D%5D%7D"}`
+	after := filepath.Join(t.TempDir(), "after.json")
+	if err := os.WriteFile(after, []byte(failing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		stdin    string
+		run      func([]string) int
+		args     []string
+		expected int
+	}{
+		{"Dash", passing, Main, []string{"-"}, 0},
+		{"DashFailing", failing, Main, []string{"-"}, 1},
+		{"Piped", roleResponse, Main, nil, 0},
+		{"PipedWithFlags", failing, Main, []string{"-wildcard", "service"}, 1},
+		{"InvalidJSON", `{"Version"`, Main, []string{"-"}, 1},
+		{"Stream", passing + "\n" + passing + "\n", Main, []string{"-stream", "-"}, 0},
+		{"Fmt", passing, runFmt, nil, 0},
+		{"FmtCheck", passing, runFmt, []string{"-check", "-"}, 1},
+		{"Optimize", passing, runOptimize, []string{"-"}, 0},
+		{"Diff", passing, runDiff, []string{"-", after}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			withStdin(t, tc.stdin)
+			if status := tc.run(tc.args); status != tc.expected {
+				t.Errorf("expected status %d, but got %d", tc.expected, status)
+			}
+		})
+	}
+}
+
+func TestStdinTerminal(t *testing.T) {
+	terminal, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer terminal.Close()
+	previous := stdin
+	stdin = terminal
+	defer func() { stdin = previous }()
+
+	if stdinPiped() {
+		t.Fatalf("Expected %s not to count as piped input", os.DevNull)
+	}
+	if status := Main(nil); status != 2 {
+		t.Errorf("Expected usage error without input file, but got status %d", status)
+	}
+}
+
+// withStdin makes standard input read the given content for the rest of
+// the test.
+func withStdin(t *testing.T, content string) {
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := stdin
+	stdin = file
+	t.Cleanup(func() {
+		stdin = previous
+		file.Close()
+	})
+}
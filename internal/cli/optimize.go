@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"test3/iampolicy"
+)
+
+// runOptimize implements the optimize subcommand and returns the exit status.
+func runOptimize(args []string) int {
+	flags := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	catalogFile := flags.String("catalog", "", "action catalog file, one service:action per line (default: embedded catalog)")
+	output := flags.String("o", "", "write the optimized policy to this file instead of standard output")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam optimize [-catalog <file>] [-o <file>] <path_to_json_file> | -")
+		fmt.Println("\nPrints a smaller policy granting the same access, with the steps justifying each change.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	inputs := inputArgs(flags)
+	if len(inputs) != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	catalog, err := iampolicy.LoadCatalog(*catalogFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	policy, err := loadPolicyFile(inputs[0])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	optimized, steps := iampolicy.Optimize(policy, catalog)
+	if len(steps) == 0 {
+		fmt.Println("Policy is already optimal")
+		return 0
+	}
+	for _, step := range steps {
+		fmt.Printf("- %s\n", step)
+	}
+	fmt.Printf("Size: %d -> %d characters\n", policy.Size(), optimized.Size())
+	result, err := iampolicy.Compare(policy, optimized, catalog)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	if result.Relation != iampolicy.Equivalent {
+		fmt.Printf("Error: optimized policy is %s to the original\n", result.Relation)
+		return 2
+	}
+	fmt.Println("Equivalence with the original policy verified against the action catalog")
+
+	if *output == "" {
+		fmt.Println()
+	}
+	if err := writePolicy(optimized.Document, "", *output); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	if *output != "" {
+		fmt.Printf("Wrote optimized policy to %s\n", *output)
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunOptimize(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "policy.json")
+	output := filepath.Join(dir, "optimized.json")
+	if err := os.WriteFile(input, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "*"}, {"Effect": "Allow", "Action": "sqs:SendMessageBatch", "Resource": "*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := runOptimize([]string{"-o", output, input}); status != 0 {
+		t.Fatalf("Expected status 0, but got %d", status)
+	}
+	fileData, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(fileData, []byte(`"sqs:Send*"`)) {
+		t.Errorf("Expected merged statements to be collapsed, got:\n%s", fileData)
+	}
+	if status := runOptimize([]string{filepath.Join(dir, "missing.json")}); status != 2 {
+		t.Errorf("Expected status 2 for missing file, but got %d", status)
+	}
+}
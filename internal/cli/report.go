@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+
+	"test3/iampolicy"
+)
+
+// printReport prints a file report as text; verbose adds the kind of a
+// single policy.
+func printReport(r *iampolicy.FileReport, verbose bool) {
+	for _, s := range r.Expired {
+		fmt.Printf("Expired suppression: %s\n", s)
+	}
+	switch {
+	case r.Policies != nil:
+		printPolicyResults(r.Policies, "")
+	case r.Roles != nil:
+		printRoleResults(r.Roles)
+	default:
+		if verbose {
+			fmt.Printf("Policy kind: %s\n", r.Kind)
+		}
+		printFindings(r.Findings, "")
+	}
+	if r.Baselined > 0 {
+		fmt.Printf("%d findings hidden by baseline\n", r.Baselined)
+	}
+}
+
+// printFindings prints the findings, followed by the suppressed ones.
+func printFindings(findings []iampolicy.Finding, indent string) {
+	var suppressed []iampolicy.Finding
+	for _, finding := range findings {
+		if finding.Suppression != nil {
+			suppressed = append(suppressed, finding)
+			continue
+		}
+		fmt.Printf("%s%s\n", indent, finding)
+	}
+	if len(suppressed) == 0 {
+		return
+	}
+	fmt.Printf("%sSuppressed:\n", indent)
+	for _, finding := range suppressed {
+		fmt.Printf("%s  %s\n", indent, finding)
+		fmt.Printf("%s    reason: %s\n", indent, finding.Suppression.Reason)
+	}
+}
+
+func printPolicyResults(results []iampolicy.PolicyResult, indent string) bool {
+	passed := true
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("%s%s: Error: %s\n", indent, result.Source, result.Err)
+			passed = false
+		} else {
+			fmt.Printf("%s%s: Result: %t\n", indent, result.Source, result.Result)
+			printFindings(result.Findings, indent+"  ")
+			passed = passed && result.Result
+		}
+	}
+	return passed
+}
+
+func printRoleResults(results []iampolicy.RoleResult) bool {
+	passed := true
+	for _, role := range results {
+		fmt.Printf("Role %s:\n", role.RoleName)
+		if !printPolicyResults(role.Results, "  ") {
+			passed = false
+		}
+	}
+	return passed
+}
+
+// printJSON prints a value as indented JSON.
+func printJSON(value interface{}) {
+	out, _ := json.MarshalIndent(value, "", "    ")
+	fmt.Println(string(out))
+}
+
+const (
+	textFormat = "text"
+	jsonFormat = "json"
+)
+
+// outputFlags are the command line flags choosing how a command prints its
+// results. Commands with more to tell register verbose themselves.
+type outputFlags struct {
+	format  string
+	quiet   bool
+	verbose bool
+}
+
+func (f *outputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.format, "format", textFormat, "output format: text or json")
+	flags.BoolVar(&f.quiet, "quiet", false, "print nothing but errors; the exit status tells the result")
+}
+
+func (f *outputFlags) check() error {
+	if f.format != textFormat && f.format != jsonFormat {
+		return fmt.Errorf("unknown output format '%s'", f.format)
+	}
+	if f.quiet && f.verbose {
+		return errors.New("-quiet and -verbose cannot be used together")
+	}
+	return nil
+}
+
+// text reports whether results are printed as text.
+func (f *outputFlags) text() bool {
+	return f.format == textFormat && !f.quiet
+}
+
+// json reports whether results are printed as JSON.
+func (f *outputFlags) json() bool {
+	return f.format == jsonFormat && !f.quiet
+}
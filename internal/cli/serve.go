@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"test3/iampolicy"
+)
+
+// defaultMaxRequestBytes limits the size of request bodies; IAM itself
+// rejects policies well below it.
+const defaultMaxRequestBytes = 1 << 20
+
+// server exposes the verifier over HTTP.
+type server struct {
+	verifier *iampolicy.Verifier
+	maxBytes int64
+	// ready is cleared when the server starts shutting down. It keeps
+	// answering requests for drainDelay before it stops accepting them, so
+	// that load balancers polling /readyz take it out of rotation first.
+	ready      atomic.Bool
+	drainDelay time.Duration
+	// onListen, when set, is called once serve accepts connections.
+	onListen func()
+}
+
+func newServer(v *iampolicy.Verifier, maxBytes int64) *server {
+	s := &server{verifier: v, maxBytes: maxBytes}
+	s.ready.Store(true)
+	return s
+}
+
+// verifyResponse is the body answering POST /v1/verify. Error is set when
+// the input is not a valid policy, in which case there are no findings.
+type verifyResponse struct {
+	Result   bool                `json:"result"`
+	Kind     string              `json:"kind,omitempty"`
+	Findings []iampolicy.Finding `json:"findings"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// evaluateRequest is the body of POST /v1/evaluate: a policy in any input
+// format verify accepts and the request to simulate.
+type evaluateRequest struct {
+	Policy  interface{}       `json:"policy"`
+	Request iampolicy.Request `json:"request"`
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/verify", s.handleVerify)
+	mux.HandleFunc("/v1/evaluate", s.handleEvaluate)
+	mux.HandleFunc("/v1/admission", s.handleAdmission)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	return mux
+}
+
+func (s *server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+	response, err := verifyPolicyText(s.verifier, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if response.Error != "" {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSONResponse(w, status, response)
+}
+
+func (s *server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	body, ok := s.readBody(w, r)
+	if !ok {
+		return
+	}
+	var request evaluateRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %s", err))
+		return
+	}
+	result, err := evaluatePolicyInput(request.Policy, request.Request)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, result)
+}
+
+// verifyPolicyText verifies a single policy given as text in any input
+// format. It fails when the text holds no single policy; a policy that is
+// not valid is answered with the validation error.
+func verifyPolicyText(v *iampolicy.Verifier, text []byte) (verifyResponse, error) {
+	result, err := v.Verify(text)
+	var invalid *iampolicy.ValidationError
+	if errors.As(err, &invalid) {
+		return verifyResponse{Findings: []iampolicy.Finding{}, Error: invalid.Error()}, nil
+	}
+	if err != nil {
+		return verifyResponse{}, err
+	}
+	findings := result.Findings
+	if findings == nil {
+		findings = []iampolicy.Finding{}
+	}
+	return verifyResponse{Result: result.Passed, Kind: result.Kind.String(), Findings: findings}, nil
+}
+
+// evaluatePolicyInput evaluates a request against a policy given either
+// decoded or as text.
+func evaluatePolicyInput(input interface{}, request iampolicy.Request) (iampolicy.Evaluation, error) {
+	text, ok := input.(string)
+	data := []byte(text)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(input); err != nil {
+			return iampolicy.Evaluation{}, err
+		}
+	}
+	policy, err := iampolicy.Parse(data)
+	if err != nil {
+		return iampolicy.Evaluation{}, err
+	}
+	return iampolicy.Evaluate(policy, request)
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeJSONResponse(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting down"})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// readBody reads the body of a POST request, answering the request itself
+// when it uses another method or its body is too large.
+func (s *server) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", s.maxBytes))
+		} else {
+			writeError(w, http.StatusBadRequest, err.Error())
+		}
+		return nil, false
+	}
+	return body, true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSONResponse(w, status, map[string]string{"error": message})
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// serve answers requests on the listener until ctx is done. It then stops
+// being ready, keeps answering for the drain delay, stops accepting
+// connections and waits up to timeout for the ones in progress.
+func (s *server) serve(ctx context.Context, listener net.Listener, timeout time.Duration) error {
+	httpServer := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		// Serve asks for the base context once it tracks the listener.
+		BaseContext: func(net.Listener) context.Context {
+			if s.onListen != nil {
+				s.onListen()
+			}
+			return context.Background()
+		},
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(s.drainDelay):
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// runServe implements the serve subcommand and returns the exit status.
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file")
+	rulesDir := flags.String("rules", "", "directory of declarative rules to load")
+	maxBytes := flags.Int64("max-body", defaultMaxRequestBytes, "maximum size of a request body in bytes")
+	drainDelay := flags.Duration("drain-delay", 0, "time to keep answering requests while /readyz reports shutdown")
+	timeout := flags.Duration("shutdown-timeout", 10*time.Second, "time to let requests in progress finish on shutdown")
+	certFile := flags.String("tls-cert", "", "TLS certificate file; admission webhooks must be served over TLS")
+	keyFile := flags.String("tls-key", "", "TLS private key file")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam serve [-addr <host:port>] [-config <config_file>] [-rules <dir>] [-max-body <bytes>] [-drain-delay <duration>] [-shutdown-timeout <duration>] [-tls-cert <file> -tls-key <file>]")
+		fmt.Println("\nServes verification over HTTP: POST /v1/verify, POST /v1/evaluate, POST /v1/admission, GET /healthz and GET /readyz.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	v, err := serverVerifier(*configFile, *rulesDir)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	listener, err := serverListener(*addr, *certFile, *keyFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := newServer(v, *maxBytes)
+	s.drainDelay = *drainDelay
+	fmt.Printf("Listening on %s\n", listener.Addr())
+	if err := s.serve(ctx, listener, *timeout); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	fmt.Println("Server stopped")
+	return 0
+}
+
+// serverVerifier returns the verifier the servers use, configured like the
+// command line.
+func serverVerifier(configFile, rulesDir string) (*iampolicy.Verifier, error) {
+	cfg, err := iampolicy.LoadConfig(configFile, rulesDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Verifier("")
+}
+
+// serverListener listens on addr, with TLS when a certificate is given.
+func serverListener(addr, certFile, keyFile string) (net.Listener, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("-tls-cert and -tls-key must be used together")
+	}
+	var certificate tls.Certificate
+	if certFile != "" {
+		var err error
+		if certificate, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if certFile != "" {
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12})
+	}
+	return listener, nil
+}
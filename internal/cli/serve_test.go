@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"test3/iampolicy"
+)
+
+// newTestVerifier returns a verifier with the default rules.
+func newTestVerifier(t *testing.T) *iampolicy.Verifier {
+	t.Helper()
+	v, err := iampolicy.NewVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestServerVerify(t *testing.T) {
+	ts := httptest.NewServer(newServer(newTestVerifier(t), 512).handler())
+	defer ts.Close()
+
+	testCases := []struct {
+		name     string
+		method   string
+		body     string
+		status   int
+		result   bool
+		findings int
+		error    string
+	}{
+		{
+			name:   "Passes",
+			method: http.MethodPost,
+			body:   `{"PolicyName": "p", "PolicyDocument": {"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}]}}`,
+			status: http.StatusOK,
+			result: true,
+		},
+		{
+			name:     "BareDocumentFails",
+			method:   http.MethodPost,
+			body:     `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+			status:   http.StatusOK,
+			findings: 1,
+		},
+		{
+			name:   "InvalidPolicy",
+			method: http.MethodPost,
+			body:   `{"Version": "2012-10-17", "Statement": [{"Action": "s3:GetObject", "Resource": "*"}]}`,
+			status: http.StatusUnprocessableEntity,
+			error:  "Effect field is missing",
+		},
+		{
+			name:   "InvalidJSON",
+			method: http.MethodPost,
+			body:   `{"Version": `,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "TooLarge",
+			method: http.MethodPost,
+			body:   `{"PolicyName": "` + strings.Repeat("p", 600) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			error:  "request body exceeds 512 bytes",
+		},
+		{
+			name:   "WrongMethod",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(tc.method, ts.URL+"/v1/verify", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != tc.status {
+				t.Fatalf("Expected status %d, but got %d", tc.status, response.StatusCode)
+			}
+			var body verifyResponse
+			if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Result != tc.result || len(body.Findings) != tc.findings {
+				t.Errorf("Expected result %t with %d findings, but got %+v", tc.result, tc.findings, body)
+			}
+			if tc.error != "" && body.Error != tc.error {
+				t.Errorf("Expected error %q, but got %q", tc.error, body.Error)
+			}
+		})
+	}
+}
+
+func TestServerEvaluate(t *testing.T) {
+	ts := httptest.NewServer(newServer(newTestVerifier(t), defaultMaxRequestBytes).handler())
+	defer ts.Close()
+
+	policy := `{"Version": "2012-10-17", "Statement": [{"Sid": "Read", "Effect": "Allow", "Action": "s3:Get*", "Resource": "arn:aws:s3:::b/*"}]}`
+	testCases := []struct {
+		body     string
+		status   int
+		decision string
+	}{
+		{`{"policy": ` + policy + `, "request": {"action": "s3:GetObject", "resource": "arn:aws:s3:::b/k"}}`, http.StatusOK, "allowed"},
+		{`{"policy": ` + policy + `, "request": {"action": "s3:PutObject", "resource": "arn:aws:s3:::b/k"}}`, http.StatusOK, "implicitDeny"},
+		{`{"policy": {"Version": "2012-10-17"}, "request": {"action": "s3:GetObject"}}`, http.StatusUnprocessableEntity, ""},
+		{`{"policy": ` + policy + `, "request": {}}`, http.StatusUnprocessableEntity, ""},
+	}
+	for _, tc := range testCases {
+		response, err := http.Post(ts.URL+"/v1/evaluate", "application/json", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Decision string `json:"decision"`
+			Error    string `json:"error"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.StatusCode != tc.status || body.Decision != tc.decision {
+			t.Errorf("%s: expected status %d and decision %q, but got %d and %+v", tc.body, tc.status, tc.decision, response.StatusCode, body)
+		}
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newServer(newTestVerifier(t), defaultMaxRequestBytes)
+	s.drainDelay = 200 * time.Millisecond
+	listening := make(chan struct{})
+	s.onListen = func() { close(listening) }
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.serve(ctx, listener, 10*time.Second)
+	}()
+	select {
+	case <-listening:
+	case err := <-done:
+		t.Fatal(err)
+	}
+
+	// Without keep-alives the client leaves no connection behind that
+	// Shutdown would wait on until it counts as idle.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	url := "http://" + listener.Addr().String()
+	status := func(path string) int {
+		response, err := client.Get(url + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if code := status(path); code != http.StatusOK {
+			t.Errorf("%s: expected status 200, but got %d", path, code)
+		}
+	}
+
+	cancel()
+	for s.ready.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if code := status("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz: expected status 503 while draining, but got %d", code)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected clean shutdown, but got %s", err)
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatal("Server did not shut down")
+	}
+	if _, err := client.Get(url + "/healthz"); err == nil {
+		t.Error("Expected server to stop accepting connections")
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"test3/iampolicy"
+)
+
+// recordReport is the JSON form of a streamed policy's result.
+type recordReport struct {
+	Record   int                 `json:"record"`
+	Passed   bool                `json:"passed"`
+	Kind     string              `json:"kind,omitempty"`
+	Findings []iampolicy.Finding `json:"findings,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// verifyStreamFile verifies every policy in an NDJSON or JSON array file.
+// As text it prints the records with findings or errors, or every record
+// when verbose, followed by a summary; as JSON it prints one line per record.
+func verifyStreamFile(v *iampolicy.Verifier, jsonFile string, workers int, output outputFlags) (bool, error) {
+	var input io.Reader = stdin
+	if jsonFile != stdinPath {
+		file, err := os.Open(jsonFile)
+		if err != nil {
+			return false, err
+		}
+		defer file.Close()
+		input = file
+
+		v, err = v.ForFile(jsonFile)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	expired := v.ExpiredSuppressions()
+	if output.text() {
+		for _, s := range expired {
+			fmt.Printf("Expired suppression: %s\n", s)
+		}
+	}
+
+	count, failed := 0, 0
+	err := v.VerifyStream(input, workers, func(record iampolicy.RecordResult) {
+		count++
+		report := recordReport{Record: record.Index + 1}
+		if record.Err != nil {
+			report.Error = record.Err.Error()
+		} else {
+			report.Passed = record.Result.Passed
+			report.Kind = record.Result.Kind.String()
+			report.Findings = record.Result.Findings
+		}
+		if !report.Passed {
+			failed++
+		}
+
+		switch {
+		case output.json():
+			line, _ := json.Marshal(report)
+			fmt.Println(string(line))
+		case !output.text():
+		case report.Error != "":
+			fmt.Printf("Record #%d: Error: %s\n", report.Record, report.Error)
+		case len(report.Findings) > 0 || output.verbose:
+			fmt.Printf("Record #%d: Result: %t\n", report.Record, report.Passed)
+			printFindings(report.Findings, "  ")
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, errors.New("no records found")
+	}
+	if output.text() {
+		fmt.Printf("Verified %d records, %d failed\n", count, failed)
+	}
+	return failed == 0 && len(expired) == 0, nil
+}
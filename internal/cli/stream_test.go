@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	streamPassing = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*"}]}`
+	streamFailing = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`
+)
+
+func TestRunStream(t *testing.T) {
+	dir := t.TempDir()
+	passing := filepath.Join(dir, "passing.ndjson")
+	failing := filepath.Join(dir, "failing.ndjson")
+	var records strings.Builder
+	for i := 0; i < 1000; i++ {
+		records.WriteString(streamPassing + "\n")
+	}
+	if err := os.WriteFile(passing, []byte(records.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(failing, []byte(records.String()+streamFailing+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.ndjson")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"-stream", passing}, 0},
+		{[]string{"-stream", "-workers", "1", passing}, 0},
+		{[]string{"-stream", failing}, 1},
+		{[]string{"-stream", empty}, 1},
+		{[]string{"-stream", filepath.Join(dir, "missing.ndjson")}, 1},
+		{[]string{"-stream", "-workers", "0", passing}, 2},
+		{[]string{"-stream", "-baseline", filepath.Join(dir, "baseline.json"), passing}, 2},
+	}
+	for _, tc := range testCases {
+		if status := Main(tc.args); status != tc.expected {
+			t.Errorf("verify_iam %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"test3/iampolicy"
+)
+
+// printUsage reports the unused statements, actions and resources, and what
+// wildcard actions were used for. It returns whether anything was unused.
+func printUsage(usage []iampolicy.StatementUsage) bool {
+	anyUnused := false
+	for _, u := range usage {
+		name := u.Statement.Name()
+		if u.Calls == 0 {
+			fmt.Printf("Statement %s: never used\n", name)
+			anyUnused = true
+			continue
+		}
+		fmt.Printf("Statement %s: %d calls\n", name, u.Calls)
+		if unused := u.UnusedActions(); len(unused) > 0 {
+			fmt.Printf("  unused actions: %s\n", strings.Join(unused, ", "))
+			anyUnused = true
+		}
+		if unused := u.UnusedResources(); len(unused) > 0 {
+			fmt.Printf("  unused resources: %s\n", strings.Join(unused, ", "))
+			anyUnused = true
+		}
+		entries := make([]string, 0, len(u.Actions))
+		for entry := range u.Actions {
+			if strings.ContainsAny(entry, "*?") {
+				entries = append(entries, entry)
+			}
+		}
+		sort.Strings(entries)
+		for _, entry := range entries {
+			fmt.Printf("  %s used as: %s\n", entry, strings.Join(u.Actions[entry], ", "))
+		}
+	}
+	return anyUnused
+}
+
+// runUnused implements the unused subcommand and returns the exit status: 0
+// when every permission was used, 1 when some were not.
+func runUnused(args []string) int {
+	flags := flag.NewFlagSet("unused", flag.ContinueOnError)
+	role := flags.String("role", "", "name or ARN of the role whose calls to consider (default: every caller)")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam unused [-role <name_or_arn>] <path_to_json_file> <cloudtrail_file_or_dir>...")
+		fmt.Println("\nReports the statements, actions and resources of a policy no call in CloudTrail logs (.json or .json.gz) used.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	policy, err := loadPolicyFile(flags.Arg(0))
+	if err != nil {
+		fmt.Printf("Error: %s: %s\n", flags.Arg(0), err)
+		return 2
+	}
+	usage, err := iampolicy.CloudTrailUsage(policy, flags.Args()[1:], *role)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	fmt.Printf("Log window: %s to %s\n", usage.First, usage.Last)
+	if printUsage(usage.Statements) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunUnused(t *testing.T) {
+	logs := writeCloudTrail(t)
+	dir := t.TempDir()
+	used := filepath.Join(dir, "used.json")
+	unused := filepath.Join(dir, "unused.json")
+	if err := os.WriteFile(used, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "arn:aws:s3:::data/*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(unused, []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:DeleteObject"], "Resource": "arn:aws:s3:::data/*"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"-role", "app", used, logs}, 0},
+		{[]string{"-role", "app", unused, logs}, 1},
+		{[]string{"-role", "nobody", used, logs}, 2},
+		{[]string{used}, 2},
+		{[]string{filepath.Join(dir, "missing.json"), logs}, 2},
+	}
+	for _, tc := range testCases {
+		if status := runUnused(tc.args); status != tc.expected {
+			t.Errorf("unused %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"test3/iampolicy"
+)
+
+// runVerify implements the verify command, run when no other command is
+// named, and returns the exit status: 0 when the input passes verification,
+// 1 when it does not.
+func runVerify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file")
+	baselineFile := flags.String("baseline", "", "report only findings not recorded in this baseline file")
+	writeBaselineFile := flags.String("write-baseline", "", "record the current findings in this baseline file")
+	rulesDir := flags.String("rules", "", "directory of declarative rules to load")
+	stream := flags.Bool("stream", false, "verify every policy in an NDJSON or JSON array file")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of policies verified in parallel with -stream")
+	var optionFlags verifyFlags
+	optionFlags.register(flags)
+	var output outputFlags
+	output.register(flags)
+	flags.BoolVar(&output.verbose, "verbose", false, "also print the rules run, the policy kind and passing -stream records")
+	flags.Usage = func() {
+		fmt.Println("Usage: go run ./cmd/verify_iam [verify] [-config <config_file>] [-rules <dir>] [-kind <kind>] [-severity <severity>] [-format text|json] [-quiet | -verbose] [-baseline <file> | -write-baseline <file> | -stream [-workers <n>]] <path_to_json_file> | -")
+		fmt.Println("\nVerifies the policies in a file against the rules. Reads standard input for \"-\", or when no file is given and input is piped.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return usageStatus(err)
+	}
+	inputs := inputArgs(flags)
+	if len(inputs) != 1 {
+		flags.Usage()
+		return 2
+	}
+	if err := output.check(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	jsonFile := inputs[0]
+
+	cfg, err := iampolicy.LoadConfig(*configFile, *rulesDir)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	v, err := cfg.Verifier(jsonFile)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+	options, err := optionFlags.options(v)
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	if *baselineFile != "" && *writeBaselineFile != "" {
+		fmt.Println("Error: -baseline and -write-baseline cannot be used together")
+		return 2
+	}
+	if *stream && (*baselineFile != "" || *writeBaselineFile != "") {
+		fmt.Println("Error: -stream cannot be used with -baseline or -write-baseline")
+		return 2
+	}
+	if *stream && *workers < 1 {
+		fmt.Println("Error: -workers must be at least 1")
+		return 2
+	}
+	if *baselineFile != "" {
+		known, err := iampolicy.LoadBaseline(*baselineFile)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return 2
+		}
+		options = append(options, iampolicy.WithBaseline(known))
+	}
+	var record *iampolicy.Baseline
+	if *writeBaselineFile != "" {
+		record = &iampolicy.Baseline{}
+		options = append(options, iampolicy.WithBaselineRecord(record))
+	}
+	if err := v.Apply(options...); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		return 2
+	}
+
+	if output.text() {
+		fmt.Printf("\nVerifying file: %s\n", inputName(jsonFile))
+		if output.verbose {
+			fmt.Printf("Rules: %s\n", strings.Join(ruleIDs(v.Rules()), ", "))
+		}
+	}
+	if *stream {
+		result, err := verifyStreamFile(v, jsonFile, *workers, output)
+		if err != nil {
+			fmt.Printf("Error: %s\n\n", err)
+			return 1
+		}
+		if output.text() {
+			fmt.Printf("Result: %t\n\n", result)
+		}
+		if !result {
+			return 1
+		}
+		return 0
+	}
+
+	report, err := verifyInputFile(v, jsonFile)
+	if err != nil {
+		if output.json() {
+			printJSON(&iampolicy.FileReport{File: jsonFile, Error: err.Error()})
+		} else {
+			fmt.Printf("Error: %s\n\n", err)
+		}
+		return 1
+	}
+	if output.text() {
+		printReport(report, output.verbose)
+	}
+	if record != nil {
+		if err := record.Write(*writeBaselineFile); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return 2
+		}
+		if output.text() {
+			fmt.Printf("Wrote %d findings to baseline %s\n\n", len(record.Findings), *writeBaselineFile)
+		}
+		return 0
+	}
+	if output.text() {
+		fmt.Printf("Result: %t\n\n", report.Passed)
+	}
+	if output.json() {
+		printJSON(report)
+	}
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// ruleIDs returns the IDs of the rules.
+func ruleIDs(rules []iampolicy.Rule) []string {
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID())
+	}
+	return ids
+}
+
+// verifyFlags are the command line flags behind the verification options.
+type verifyFlags struct {
+	kind         string
+	versions     string
+	strictFields bool
+	rules        string
+	wildcard     string
+	severity     string
+}
+
+func (f *verifyFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.kind, "kind", "", "verify input as an inline, managed or trust policy instead of detecting it")
+	flags.StringVar(&f.versions, "allowed-versions", "", "comma-separated Version values to accept instead of 2012-10-17 and 2008-10-17")
+	flags.BoolVar(&f.strictFields, "strict-fields", true, "report fields IAM does not define in policy documents and statements")
+	flags.StringVar(&f.rules, "only-rules", "", "comma-separated IDs of the rules to run instead of the configured ones")
+	flags.StringVar(&f.wildcard, "wildcard", "", "Resource values the wildcard-resource rule flags: asterisk, service or any")
+	flags.StringVar(&f.severity, "severity", "error", "lowest severity of unsuppressed findings failing verification: info, warning or error")
+}
+
+// options returns the options set by the flags. Rules named by -only-rules
+// keep their configuration in v when they have one.
+func (f *verifyFlags) options(v *iampolicy.Verifier) ([]iampolicy.Option, error) {
+	options := []iampolicy.Option{iampolicy.WithStrictFields(f.strictFields)}
+	if f.kind != "" {
+		kind, err := iampolicy.ParsePolicyKind(f.kind)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, iampolicy.WithPolicyKind(kind))
+	}
+	if f.versions != "" {
+		options = append(options, iampolicy.WithAllowedVersions(splitFlagList(f.versions)...))
+	}
+	if f.rules != "" {
+		var rules []iampolicy.Rule
+		for _, id := range splitFlagList(f.rules) {
+			rule, ok := v.Rule(id)
+			if !ok {
+				return nil, errors.New("unknown rule " + id)
+			}
+			rules = append(rules, rule)
+		}
+		options = append(options, iampolicy.WithRules(rules...))
+	}
+	if f.wildcard != "" {
+		wildcard, err := iampolicy.ParseWildcardPolicy(f.wildcard)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, iampolicy.WithWildcardPolicy(wildcard))
+	}
+	severity, err := iampolicy.ParseSeverity(f.severity)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, iampolicy.WithSeverityThreshold(severity))
+	return options, nil
+}
+
+func splitFlagList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyFlags(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(policy, []byte(`{"Version": "2012-10-17", "Statement": [{"Sid": "Objects", "Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::data/*", "Comment": "read"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{policy}, 1},
+		{[]string{"-strict-fields=false", policy}, 0},
+		{[]string{"-strict-fields=false", "-wildcard", "any", policy}, 1},
+		{[]string{"-strict-fields=false", "-wildcard", "any", "-only-rules", "sid-format", policy}, 0},
+		{[]string{"-strict-fields=false", "-kind", "trust", policy}, 1},
+		{[]string{"-strict-fields=false", "-allowed-versions", "2008-10-17", policy}, 1},
+		{[]string{"-kind", "group", policy}, 2},
+		{[]string{"-wildcard", "some", policy}, 2},
+		{[]string{"-only-rules", "no-such-rule", policy}, 2},
+	}
+	for _, tc := range testCases {
+		if status := Main(tc.args); status != tc.expected {
+			t.Errorf("verify_iam %v: expected status %d, but got %d", tc.args, tc.expected, status)
+		}
+	}
+}
+
+func TestWriteBaselineInvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "garbage.json")
+	baselineFile := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(policyFile, []byte("not a policy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := Main([]string{"-write-baseline", baselineFile, policyFile}); status != 1 {
+		t.Errorf("Expected status 1, but got %d", status)
+	}
+	if _, err := os.Stat(baselineFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no baseline to be written, but got %v", err)
+	}
+}